@@ -40,32 +40,46 @@ type AttachmentOrchestrator struct {
 	FormatCaptionToHTML func(caption string) string
 }
 
+// SentAttachment describes a delivered attachment message and the OneDrive item backing it.
+type SentAttachment struct {
+	ClientMessageID string
+	DriveItemID     string
+}
+
 func (o *AttachmentOrchestrator) SendAttachmentMessage(ctx context.Context, threadID string, filename string, content []byte, caption string) (string, error) {
+	sent, err := o.SendAttachment(ctx, threadID, filename, content, caption)
+	if err != nil {
+		return "", err
+	}
+	return sent.ClientMessageID, nil
+}
+
+func (o *AttachmentOrchestrator) SendAttachment(ctx context.Context, threadID string, filename string, content []byte, caption string) (*SentAttachment, error) {
 	if strings.TrimSpace(threadID) == "" {
-		return "", errors.New("missing thread id")
+		return nil, errors.New("missing thread id")
 	}
 	if strings.TrimSpace(filename) == "" {
-		return "", errors.New("missing filename")
+		return nil, errors.New("missing filename")
 	}
 	if len(content) == 0 {
-		return "", errors.New("missing content")
+		return nil, errors.New("missing content")
 	}
 	maxBytes := o.MaxBytes
 	if maxBytes <= 0 {
 		maxBytes = MaxAttachmentBytesV0
 	}
 	if len(content) > maxBytes {
-		return "", errors.New("attachment exceeds max size")
+		return nil, errors.New("attachment exceeds max size")
 	}
 	if o.Graph == nil {
-		return "", errors.New("missing graph client")
+		return nil, errors.New("missing graph client")
 	}
 	if o.Teams == nil {
-		return "", errors.New("missing teams client")
+		return nil, errors.New("missing teams client")
 	}
 	fromUserID := strings.TrimSpace(o.FromUserID)
 	if fromUserID == "" {
-		return "", errors.New("missing from user id")
+		return nil, errors.New("missing from user id")
 	}
 
 	genID := o.GenerateMessageID
@@ -92,7 +106,7 @@ func (o *AttachmentOrchestrator) SendAttachmentMessage(ctx context.Context, thre
 	uploaded, err := o.Graph.UploadTeamsChatFile(ctx, filename, content)
 	if err != nil {
 		log.Err(err).Str("phase", "upload").Msg("send_attachment failed")
-		return "", err
+		return nil, err
 	}
 	log.Info().
 		Str("listItemUniqueID", strings.TrimSpace(uploaded.ListItemUniqueID)).
@@ -101,7 +115,7 @@ func (o *AttachmentOrchestrator) SendAttachmentMessage(ctx context.Context, thre
 	share, err := o.Graph.CreateShareLink(ctx, uploaded.ListItemUniqueID)
 	if err != nil {
 		log.Err(err).Str("phase", "create_link").Msg("send_attachment failed")
-		return "", err
+		return nil, err
 	}
 	log.Info().
 		Str("listItemUniqueID", strings.TrimSpace(uploaded.ListItemUniqueID)).
@@ -111,7 +125,7 @@ func (o *AttachmentOrchestrator) SendAttachmentMessage(ctx context.Context, thre
 	filesStr, err := attachments.BuildTeamsAttachmentFilesProperty(uploaded, share, filename, ext)
 	if err != nil {
 		log.Err(err).Str("phase", "build_files").Msg("send_attachment failed")
-		return "", err
+		return nil, err
 	}
 
 	htmlContent := ""
@@ -122,13 +136,16 @@ func (o *AttachmentOrchestrator) SendAttachmentMessage(ctx context.Context, thre
 	_, err = o.Teams.SendAttachmentMessageWithID(ctx, threadID, htmlContent, filesStr, fromUserID, clientMessageID)
 	if err != nil {
 		log.Err(err).Str("phase", "teams_send").Msg("send_attachment failed")
-		return "", err
+		return nil, err
 	}
 	log.Info().
 		Str("listItemUniqueID", strings.TrimSpace(uploaded.ListItemUniqueID)).
 		Msg("teams_send_success")
 
-	return clientMessageID, nil
+	return &SentAttachment{
+		ClientMessageID: clientMessageID,
+		DriveItemID:     strings.TrimSpace(uploaded.DriveItemID),
+	}, nil
 }
 
 func formatCaptionHTML(text string) string {
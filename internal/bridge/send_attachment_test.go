@@ -128,6 +128,33 @@ func TestSendAttachmentMessageSuccess(t *testing.T) {
 	}
 }
 
+func TestSendAttachmentReturnsDriveItemID(t *testing.T) {
+	mg := &mockGraph{
+		uploaded: &graph.UploadedDriveItem{
+			DriveItemID:      "CID!sabc123",
+			ListItemUniqueID: "11111111-2222-3333-4444-555555555555",
+			SiteURL:          "https://tenant-my.sharepoint.com/personal/user",
+			FileName:         "spec.pdf",
+			Size:             123,
+		},
+		share: &graph.CreatedShareLink{ShareID: "u!abc123", ShareURL: "https://1drv.ms/u/s!abc123"},
+	}
+	orch := &AttachmentOrchestrator{
+		Graph:             mg,
+		Teams:             &mockTeams{},
+		FromUserID:        "8:live:me",
+		GenerateMessageID: func() string { return "999" },
+	}
+
+	sent, err := orch.SendAttachment(context.Background(), "@19:abc@thread.v2", "spec.pdf", []byte("hello"), "")
+	if err != nil {
+		t.Fatalf("SendAttachment failed: %v", err)
+	}
+	if sent.ClientMessageID != "999" || sent.DriveItemID != "CID!sabc123" {
+		t.Fatalf("unexpected sent attachment: %#v", sent)
+	}
+}
+
 func TestSendAttachmentMessageUploadFails(t *testing.T) {
 	mg := &mockGraph{uploadErr: errors.New("upload failed")}
 	mt := &mockTeams{}
@@ -0,0 +1,239 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.mau.fi/mautrix-teams/internal/teams/model"
+)
+
+const defaultAMSURL = "https://api.asm.skype.com/v1/objects"
+
+var ErrNotAMSURL = errors.New("not an AMS object url")
+var ErrAMSContentTooLarge = errors.New("AMS object exceeds size limit")
+
+type AMSError struct {
+	Status      int
+	BodySnippet string
+}
+
+func (e AMSError) Error() string {
+	return "AMS request failed"
+}
+
+type AMSContent struct {
+	Bytes       []byte
+	ContentType string
+}
+
+// AMSImage is an image uploaded to AMS that can be embedded in a message.
+type AMSImage struct {
+	ObjectID string
+	// ViewURL is the imgo view Teams clients render in the message body.
+	ViewURL string
+	Width   int
+	Height  int
+}
+
+// AMSAudio is a voice clip uploaded to AMS.
+type AMSAudio struct {
+	ObjectID string
+	// ObjectURL is the object itself; Teams clients append /views/audio.
+	ObjectURL string
+	Filename  string
+	Size      int
+	Duration  time.Duration
+}
+
+// UploadAMSImage creates a pish/image object readable by the members of threadID
+// and uploads data as its imgpsh content. Unlike file attachments this only needs
+// the skypetoken.
+func (c *Client) UploadAMSImage(ctx context.Context, threadID string, filename string, contentType string, data []byte) (*AMSImage, error) {
+	objectID, objectURL, err := c.uploadAMSObject(ctx, threadID, "pish/image", "imgpsh", filename, contentType, data)
+	if err != nil {
+		return nil, err
+	}
+	return &AMSImage{
+		ObjectID: objectID,
+		ViewURL:  objectURL + "/views/imgo",
+	}, nil
+}
+
+// UploadAMSAudio creates a sharing/audio object readable by the members of
+// threadID and uploads data as its audio content.
+func (c *Client) UploadAMSAudio(ctx context.Context, threadID string, filename string, contentType string, data []byte) (*AMSAudio, error) {
+	objectID, objectURL, err := c.uploadAMSObject(ctx, threadID, "sharing/audio", "audio", filename, contentType, data)
+	if err != nil {
+		return nil, err
+	}
+	return &AMSAudio{
+		ObjectID:  objectID,
+		ObjectURL: objectURL,
+		Filename:  strings.TrimSpace(filename),
+		Size:      len(data),
+	}, nil
+}
+
+func (c *Client) uploadAMSObject(ctx context.Context, threadID string, objectType string, contentName string, filename string, contentType string, data []byte) (string, string, error) {
+	if c == nil || c.HTTP == nil {
+		return "", "", ErrMissingHTTPClient
+	}
+	if c.Token == "" {
+		return "", "", ErrMissingToken
+	}
+	threadID = strings.TrimSpace(threadID)
+	if threadID == "" {
+		return "", "", errors.New("missing thread id")
+	}
+	if len(data) == 0 {
+		return "", "", errors.New("missing AMS content")
+	}
+	baseURL := c.AMSURL
+	if baseURL == "" {
+		baseURL = defaultAMSURL
+	}
+	baseURL = strings.TrimSuffix(baseURL, "/")
+
+	objectID, err := c.createAMSObject(ctx, baseURL, threadID, objectType, filename)
+	if err != nil {
+		return "", "", err
+	}
+	objectURL := baseURL + "/" + url.PathEscape(objectID)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, objectURL+"/content/"+contentName, bytes.NewReader(data))
+	if err != nil {
+		return "", "", err
+	}
+	req.Header.Set("Authorization", "skype_token "+c.Token)
+	if contentType = strings.TrimSpace(contentType); contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	req.Header.Set("Content-Type", contentType)
+	c.debugRequest("teams AMS upload request", req.URL.String(), req)
+
+	resp, err := c.executor().Do(WithRequestMeta(ctx, RequestMeta{Operation: "teams AMS upload"}), req, classifyAMSResponse)
+	if resp != nil && resp.Body != nil {
+		_ = resp.Body.Close()
+	}
+	if err != nil {
+		return "", "", err
+	}
+	return objectID, objectURL, nil
+}
+
+func (c *Client) createAMSObject(ctx context.Context, baseURL string, threadID string, objectType string, filename string) (string, error) {
+	payload := map[string]interface{}{
+		"type":        objectType,
+		"permissions": map[string][]string{threadID: {"read"}},
+	}
+	if filename = strings.TrimSpace(filename); filename != "" {
+		payload["filename"] = filename
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, baseURL, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "skype_token "+c.Token)
+	req.Header.Set("Content-Type", "application/json")
+	c.debugRequest("teams AMS create request", baseURL, req)
+
+	resp, err := c.executor().Do(WithRequestMeta(ctx, RequestMeta{Operation: "teams AMS create"}), req, classifyAMSResponse)
+	if err != nil {
+		if resp != nil && resp.Body != nil {
+			_ = resp.Body.Close()
+		}
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var created struct {
+		ID string `json:"id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return "", fmt.Errorf("failed to decode AMS object: %w", err)
+	}
+	if strings.TrimSpace(created.ID) == "" {
+		return "", errors.New("AMS response missing object id")
+	}
+	return strings.TrimSpace(created.ID), nil
+}
+
+// DownloadAMSObject fetches an AMS object view (e.g. .../views/imgo) with the skypetoken.
+// The URL must point at an AMS host so the token can't leak to arbitrary servers.
+func (c *Client) DownloadAMSObject(ctx context.Context, objectURL string, maxBytes int64) (*AMSContent, error) {
+	if c == nil || c.HTTP == nil {
+		return nil, ErrMissingHTTPClient
+	}
+	if c.Token == "" {
+		return nil, ErrMissingToken
+	}
+	parsed, err := url.Parse(strings.TrimSpace(objectURL))
+	if err != nil || parsed.Scheme != "https" || !model.IsAMSHost(parsed.Hostname()) {
+		return nil, ErrNotAMSURL
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, parsed.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "skype_token "+c.Token)
+	c.debugRequest("teams AMS download request", parsed.String(), req)
+
+	ctx = WithRequestMeta(ctx, RequestMeta{Operation: "teams AMS download"})
+	resp, err := c.executor().Do(ctx, req, classifyAMSResponse)
+	if err != nil {
+		if resp != nil && resp.Body != nil {
+			_ = resp.Body.Close()
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// Read at most maxBytes+1 to enforce a hard cap.
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxBytes {
+		return nil, ErrAMSContentTooLarge
+	}
+	return &AMSContent{
+		Bytes:       data,
+		ContentType: strings.TrimSpace(resp.Header.Get("Content-Type")),
+	}, nil
+}
+
+func classifyAMSResponse(resp *http.Response) error {
+	if resp == nil {
+		return errors.New("missing response")
+	}
+	if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
+		return nil
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return RetryableError{
+			Status:     resp.StatusCode,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
+	}
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return RetryableError{Status: resp.StatusCode}
+	}
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+	return AMSError{
+		Status:      resp.StatusCode,
+		BodySnippet: string(snippet),
+	}
+}
@@ -0,0 +1,216 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.mau.fi/mautrix-teams/internal/teams/model"
+)
+
+type amsRoundTripper struct {
+	requests []*http.Request
+	status   int
+	body     string
+}
+
+func (rt *amsRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.requests = append(rt.requests, req)
+	return &http.Response{
+		StatusCode: rt.status,
+		Header:     http.Header{"Content-Type": []string{"image/png"}},
+		Body:       io.NopCloser(strings.NewReader(rt.body)),
+		Request:    req,
+	}, nil
+}
+
+func TestDownloadAMSObject(t *testing.T) {
+	rt := &amsRoundTripper{status: http.StatusOK, body: "pngdata"}
+	c := NewClient(&http.Client{Transport: rt})
+	c.Token = "token123"
+
+	content, err := c.DownloadAMSObject(context.Background(), "https://eu-api.asm.skype.com/v1/objects/0-weu-d1-abc/views/imgo", 16)
+	if err != nil {
+		t.Fatalf("DownloadAMSObject failed: %v", err)
+	}
+	if string(content.Bytes) != "pngdata" || content.ContentType != "image/png" {
+		t.Fatalf("unexpected content: %#v", content)
+	}
+	if len(rt.requests) != 1 || rt.requests[0].Header.Get("Authorization") != "skype_token token123" {
+		t.Fatalf("unexpected requests: %#v", rt.requests)
+	}
+
+	if _, err = c.DownloadAMSObject(context.Background(), "https://eu-api.asm.skype.com/v1/objects/0-weu-d1-abc/views/imgo", 3); !errors.Is(err, ErrAMSContentTooLarge) {
+		t.Fatalf("expected size limit error, got %v", err)
+	}
+}
+
+func TestDownloadAMSObjectRejectsOtherHosts(t *testing.T) {
+	rt := &amsRoundTripper{status: http.StatusOK}
+	c := NewClient(&http.Client{Transport: rt})
+	c.Token = "token123"
+
+	for _, input := range []string{
+		"https://example.com/v1/objects/abc/views/imgo",
+		"http://eu-api.asm.skype.com/v1/objects/abc/views/imgo",
+		"https://asm.skype.com.example.com/v1/objects/abc/views/imgo",
+	} {
+		if _, err := c.DownloadAMSObject(context.Background(), input, 16); !errors.Is(err, ErrNotAMSURL) {
+			t.Fatalf("expected ErrNotAMSURL for %q, got %v", input, err)
+		}
+	}
+	if len(rt.requests) != 0 {
+		t.Fatalf("token must not be sent to non-AMS hosts, got %d requests", len(rt.requests))
+	}
+}
+
+func TestDownloadAMSObjectError(t *testing.T) {
+	rt := &amsRoundTripper{status: http.StatusForbidden, body: "denied"}
+	c := NewClient(&http.Client{Transport: rt})
+	c.Token = "token123"
+
+	_, err := c.DownloadAMSObject(context.Background(), "https://eu-api.asm.skype.com/v1/objects/abc/views/imgo", 16)
+	var amsErr AMSError
+	if !errors.As(err, &amsErr) || amsErr.Status != http.StatusForbidden || amsErr.BodySnippet != "denied" {
+		t.Fatalf("expected AMSError, got %#v", err)
+	}
+}
+
+func TestUploadAMSImage(t *testing.T) {
+	var createPayload struct {
+		Type        string              `json:"type"`
+		Permissions map[string][]string `json:"permissions"`
+		Filename    string              `json:"filename"`
+	}
+	var uploaded []byte
+	var uploadType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "skype_token token123" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/objects":
+			if err := json.NewDecoder(r.Body).Decode(&createPayload); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":"0-weu-d1-abc"}`))
+		case r.Method == http.MethodPut && r.URL.Path == "/v1/objects/0-weu-d1-abc/content/imgpsh":
+			uploaded, _ = io.ReadAll(r.Body)
+			uploadType = r.Header.Get("Content-Type")
+			w.WriteHeader(http.StatusCreated)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	c := NewClient(server.Client())
+	c.AMSURL = server.URL + "/v1/objects"
+	c.Token = "token123"
+
+	image, err := c.UploadAMSImage(context.Background(), "19:abc@thread.v2", "cat.png", "image/png", []byte("pngdata"))
+	if err != nil {
+		t.Fatalf("UploadAMSImage failed: %v", err)
+	}
+	if createPayload.Type != "pish/image" || createPayload.Filename != "cat.png" {
+		t.Fatalf("unexpected create payload: %#v", createPayload)
+	}
+	if perms := createPayload.Permissions["19:abc@thread.v2"]; len(perms) != 1 || perms[0] != "read" {
+		t.Fatalf("unexpected permissions: %#v", createPayload.Permissions)
+	}
+	if string(uploaded) != "pngdata" || uploadType != "image/png" {
+		t.Fatalf("unexpected upload: %q (%s)", uploaded, uploadType)
+	}
+	if image.ObjectID != "0-weu-d1-abc" || image.ViewURL != server.URL+"/v1/objects/0-weu-d1-abc/views/imgo" {
+		t.Fatalf("unexpected image: %#v", image)
+	}
+}
+
+func TestSendAMSImageWithIDPayload(t *testing.T) {
+	var payload struct {
+		Content    string            `json:"content"`
+		Properties map[string]string `json:"properties"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	c := NewClient(server.Client())
+	c.SendMessagesURL = server.URL + "/conversations"
+	c.Token = "token123"
+
+	image := AMSImage{
+		ObjectID: "0-weu-d1-abc",
+		ViewURL:  "https://api.asm.skype.com/v1/objects/0-weu-d1-abc/views/imgo",
+		Width:    300,
+		Height:   200,
+	}
+	if _, err := c.SendAMSImageWithID(context.Background(), "19:abc@thread.v2", image, PlainTextBody("look", nil), "8:live:me", "1"); err != nil {
+		t.Fatalf("SendAMSImageWithID failed: %v", err)
+	}
+	want := `<p><img itemscope="" itemtype="http://schema.skype.com/AMSImage" src="https://api.asm.skype.com/v1/objects/0-weu-d1-abc/views/imgo" id="x_0-weu-d1-abc"` +
+		` width="300" height="200" style="vertical-align:bottom; width: 300px; height: 200px;"></p><p>look</p>`
+	if payload.Content != want {
+		t.Fatalf("unexpected content:\nwant: %s\ngot:  %s", want, payload.Content)
+	}
+
+	// The outbound markup must be recognized by the inbound parser.
+	images := model.ParseInlineImagesFromHTML(payload.Content)
+	if len(images) != 1 || images[0].ObjectID != "0-weu-d1-abc" || images[0].Width != 300 {
+		t.Fatalf("unexpected parsed images: %#v", images)
+	}
+}
+
+func TestSendAudioMessageWithIDPayload(t *testing.T) {
+	var payload struct {
+		Content     string `json:"content"`
+		MessageType string `json:"messagetype"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	c := NewClient(server.Client())
+	c.SendMessagesURL = server.URL + "/conversations"
+	c.Token = "token123"
+
+	audio := AMSAudio{
+		ObjectID:  "0-weu-d1-abc",
+		ObjectURL: "https://api.asm.skype.com/v1/objects/0-weu-d1-abc",
+		Filename:  "voice.ogg",
+		Size:      2048,
+		Duration:  3500 * time.Millisecond,
+	}
+	if _, err := c.SendAudioMessageWithID(context.Background(), "19:abc@thread.v2", audio, "8:live:me", "1"); err != nil {
+		t.Fatalf("SendAudioMessageWithID failed: %v", err)
+	}
+	if payload.MessageType != model.AudioMessageType {
+		t.Fatalf("unexpected messagetype: %q", payload.MessageType)
+	}
+
+	// The outbound URIObject must be recognized by the inbound parser.
+	content, _ := json.Marshal(payload.Content)
+	parsed := model.ParseAudioMessage(payload.MessageType, content)
+	if parsed == nil || parsed.ObjectID != "0-weu-d1-abc" || parsed.Filename != "voice.ogg" || parsed.Size != 2048 || parsed.Duration != audio.Duration {
+		t.Fatalf("unexpected parsed audio: %#v", parsed)
+	}
+}
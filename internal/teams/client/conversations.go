@@ -36,6 +36,7 @@ type Client struct {
 	MessagesURL            string
 	SendMessagesURL        string
 	ConsumptionHorizonsURL string
+	AMSURL                 string
 	Token                  string
 	Log                    *zerolog.Logger
 }
@@ -54,6 +55,7 @@ func NewClient(httpClient *http.Client) *Client {
 		ConversationsURL:       defaultConversationsURL,
 		SendMessagesURL:        defaultSendMessagesURL,
 		ConsumptionHorizonsURL: defaultConsumptionHorizonsURL,
+		AMSURL:                 defaultAMSURL,
 	}
 }
 
@@ -0,0 +1,77 @@
+package client
+
+import (
+	"strconv"
+	"strings"
+
+	"go.mau.fi/mautrix-teams/internal/teams/model"
+)
+
+// Mention is an outbound @mention of a Teams user in a plain-text message.
+type Mention struct {
+	MRI         string
+	DisplayName string
+}
+
+// MessageBody is outbound Teams RichText/Html content together with the
+// mentions whose spans it contains.
+type MessageBody struct {
+	HTML     string
+	Mentions []model.MessageMention
+	// Subject and Importance are sent as message properties when set.
+	Subject    string
+	Importance model.MessageImportance
+}
+
+// PlainTextBody renders text like formatHTMLContent, wrapping the first
+// occurrence of each mention's display name (in order) in a Teams mention span.
+// Mentions whose name doesn't appear in the text are dropped, since Teams only
+// notifies users that have a span in the content.
+func PlainTextBody(text string, mentions []Mention) MessageBody {
+	text = normalizeNewlines(text)
+	if len(mentions) == 0 {
+		return MessageBody{HTML: formatHTMLContent(text)}
+	}
+
+	var b strings.Builder
+	var placed []model.MessageMention
+	rest := text
+	for _, mention := range mentions {
+		mri := strings.TrimSpace(mention.MRI)
+		name := strings.TrimSpace(mention.DisplayName)
+		if mri == "" || name == "" {
+			continue
+		}
+		idx := strings.Index(rest, name)
+		if idx < 0 {
+			continue
+		}
+		itemID := strconv.Itoa(len(placed))
+		b.WriteString(escapeHTMLText(rest[:idx]))
+		b.WriteString(model.FormatMentionSpan(itemID, escapeHTMLText(name)))
+		rest = rest[idx+len(name):]
+		placed = append(placed, model.MessageMention{ItemID: itemID, MRI: mri, DisplayName: name})
+	}
+	if len(placed) == 0 {
+		return MessageBody{HTML: formatHTMLContent(text)}
+	}
+	b.WriteString(escapeHTMLText(rest))
+	return MessageBody{HTML: "<p>" + b.String() + "</p>", Mentions: placed}
+}
+
+func (body MessageBody) properties() map[string]string {
+	properties := make(map[string]string)
+	if encoded := model.EncodeMentionsProperty(body.Mentions); encoded != "" {
+		properties["mentions"] = encoded
+	}
+	if subject := strings.TrimSpace(body.Subject); subject != "" {
+		properties["subject"] = subject
+	}
+	if body.Importance != model.ImportanceNormal {
+		properties["importance"] = string(body.Importance)
+	}
+	if len(properties) == 0 {
+		return nil
+	}
+	return properties
+}
@@ -0,0 +1,104 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.mau.fi/mautrix-teams/internal/teams/model"
+)
+
+func TestSendMessageWithIDMentions(t *testing.T) {
+	var payload struct {
+		Content    string            `json:"content"`
+		Properties map[string]string `json:"properties"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.Client())
+	client.SendMessagesURL = server.URL + "/conversations"
+	client.Token = "token123"
+
+	mentions := []Mention{
+		{MRI: "8:live:alice", DisplayName: "Alice <A>"},
+		{MRI: "8:live:missing", DisplayName: "Nobody"},
+		{MRI: "8:live:bob", DisplayName: "Bob"},
+	}
+	if _, err := client.SendMessageWithID(context.Background(), "19:abc@thread.v2", PlainTextBody("hi Alice <A> and Bob\nbye", mentions), "8:live:me", "1"); err != nil {
+		t.Fatalf("SendMessageWithID failed: %v", err)
+	}
+	want := `<p>hi <span itemscope="" itemtype="http://schema.skype.com/Mention" itemid="0">Alice &lt;A&gt;</span> and ` +
+		`<span itemscope="" itemtype="http://schema.skype.com/Mention" itemid="1">Bob</span><br>bye</p>`
+	if payload.Content != want {
+		t.Fatalf("unexpected content:\nwant: %s\ngot:  %s", want, payload.Content)
+	}
+
+	// The property must round-trip through the inbound parser.
+	properties, _ := json.Marshal(payload.Properties)
+	got := model.ExtractMentions(properties)
+	if len(got) != 2 || got[0].ItemID != "0" || got[0].MRI != "8:live:alice" || got[1].ItemID != "1" || got[1].MRI != "8:live:bob" {
+		t.Fatalf("unexpected mentions property: %#v", got)
+	}
+}
+
+func TestPlainTextBodyWithoutMatches(t *testing.T) {
+	body := PlainTextBody("hello", []Mention{{MRI: "8:live:alice", DisplayName: "Alice"}})
+	if body.HTML != "<p>hello</p>" || body.Mentions != nil || body.properties() != nil {
+		t.Fatalf("unexpected body: %#v", body)
+	}
+}
+
+func TestForwardedBodyRoundTrip(t *testing.T) {
+	origin := model.MessageForward{
+		MessageID:  "1700000000000",
+		SenderID:   "8:live:alice",
+		SenderName: "Alice",
+		Timestamp:  time.UnixMilli(1700000000000),
+	}
+	body := ForwardedBody(PlainTextBody("hello", nil), origin)
+	content := model.NormalizeMessageBody(body.HTML)
+	if content.ForwardedFrom == nil || content.ForwardedFrom.SenderID != "8:live:alice" || content.ForwardedFrom.SenderName != "Alice" ||
+		!content.ForwardedFrom.Timestamp.Equal(origin.Timestamp) || content.ForwardedFrom.MessageID != "1700000000000" {
+		t.Fatalf("unexpected forward: %#v", content.ForwardedFrom)
+	}
+	if content.Body != "hello" {
+		t.Fatalf("unexpected body: %q", content.Body)
+	}
+}
+
+func TestForwardedBodyNameOnly(t *testing.T) {
+	body := ForwardedBody(PlainTextBody("hello", nil), model.MessageForward{SenderName: "Alice"})
+	if !strings.Contains(body.HTML, `<strong itemprop="mri">Alice</strong>`) {
+		t.Fatalf("expected the sender without an empty itemid, got %s", body.HTML)
+	}
+	content := model.NormalizeMessageBody(body.HTML)
+	if content.ForwardedFrom == nil || content.ForwardedFrom.SenderName != "Alice" || content.ForwardedFrom.SenderID != "" {
+		t.Fatalf("unexpected forward: %#v", content.ForwardedFrom)
+	}
+}
+
+func TestMessageBodyImportanceProperties(t *testing.T) {
+	body := PlainTextBody("hello", nil)
+	body.Subject = "Launch"
+	body.Importance = model.ImportanceUrgent
+	body = ForwardedBody(body, model.MessageForward{SenderID: "8:live:alice"})
+
+	properties, _ := json.Marshal(body.properties())
+	if subject := model.ExtractSubject(properties); subject != "Launch" {
+		t.Fatalf("unexpected subject: %q", subject)
+	}
+	if importance := model.ExtractImportance(properties); importance != model.ImportanceUrgent {
+		t.Fatalf("unexpected importance: %q", importance)
+	}
+}
@@ -44,6 +44,15 @@ func (e SendMessageError) Error() string {
 	return "send message request failed"
 }
 
+type DeleteMessageError struct {
+	Status      int
+	BodySnippet string
+}
+
+func (e DeleteMessageError) Error() string {
+	return "delete message request failed"
+}
+
 type remoteMessage struct {
 	ID                     string          `json:"id"`
 	ClientMessageID        string          `json:"clientmessageid"`
@@ -52,8 +61,10 @@ type remoteMessage struct {
 	From                   json.RawMessage `json:"from"`
 	IMDisplayName          string          `json:"imdisplayname"`
 	FromDisplayNameInToken string          `json:"fromDisplayNameInToken"`
+	MessageType            string          `json:"messagetype"`
 	Content                json.RawMessage `json:"content"`
 	Properties             json.RawMessage `json:"properties"`
+	SkypeEditedID          string          `json:"skypeeditedid"`
 }
 
 func (c *Client) ListMessages(ctx context.Context, conversationID string, sinceSequence string) ([]model.RemoteMessage, error) {
@@ -102,6 +113,17 @@ func (c *Client) ListMessages(ctx context.Context, conversationID string, sinceS
 				Msg("teams message missing sender id")
 		}
 		content := model.ExtractContent(msg.Content)
+		contentHTML, _ := model.ContentHTML(msg.Content)
+		cards := model.ParseCards(msg.Content, msg.Properties)
+		kind := model.ParseMessageKind(msg.MessageType)
+		var activity *model.ThreadActivity
+		var call *model.CallEvent
+		switch kind {
+		case model.MessageKindThreadActivity:
+			activity = model.ParseThreadActivity(msg.MessageType, msg.Content)
+		case model.MessageKindCall:
+			call = model.ParseCallEvent(msg.Content)
+		}
 		result = append(result, model.RemoteMessage{
 			MessageID:        msg.ID,
 			ClientMessageID:  msg.ClientMessageID,
@@ -110,11 +132,31 @@ func (c *Client) ListMessages(ctx context.Context, conversationID string, sinceS
 			IMDisplayName:    msg.IMDisplayName,
 			TokenDisplayName: msg.FromDisplayNameInToken,
 			Timestamp:        model.ParseTimestamp(msg.OriginalArrivalTime),
+			MessageType:      strings.TrimSpace(msg.MessageType),
+			Kind:             kind,
 			Body:             content.Body,
 			FormattedBody:    content.FormattedBody,
+			ContentHTML:      contentHTML,
 			GIFs:             content.GIFs,
+			InlineImages:     content.InlineImages,
+			ReplyTo:          content.ReplyTo,
+			ForwardedFrom:    content.ForwardedFrom,
+			Mentions:         model.ExtractMentions(msg.Properties),
 			PropertiesFiles:  model.ExtractFilesProperty(msg.Properties),
 			Reactions:        model.ExtractReactions(msg.Properties),
+			EditedMessageID:  strings.TrimSpace(msg.SkypeEditedID),
+			EditTime:         model.ExtractEditTime(msg.Properties),
+			DeleteTime:       model.ExtractDeleteTime(msg.Properties),
+			ThreadActivity:   activity,
+			Audio:            model.ParseAudioMessage(msg.MessageType, msg.Content),
+			Call:             call,
+			Cards:            cards,
+			Poll:             model.ParsePoll(cards),
+			Location:         model.ParseLocation(msg.MessageType, msg.Content),
+			Contacts:         model.ParseContacts(msg.MessageType, msg.Content),
+			LinkPreviews:     model.ExtractLinkPreviews(msg.Properties),
+			Subject:          model.ExtractSubject(msg.Properties),
+			Importance:       model.ExtractImportance(msg.Properties),
 		})
 	}
 
@@ -142,7 +184,7 @@ func GenerateClientMessageID() string {
 
 func (c *Client) SendMessage(ctx context.Context, threadID string, text string, fromUserID string) (string, error) {
 	clientMessageID := GenerateClientMessageID()
-	_, err := c.SendMessageWithID(ctx, threadID, text, fromUserID, clientMessageID)
+	_, err := c.SendMessageWithID(ctx, threadID, PlainTextBody(text, nil), fromUserID, clientMessageID)
 	return clientMessageID, err
 }
 
@@ -152,26 +194,57 @@ func (c *Client) SendGIF(ctx context.Context, threadID string, gifURL string, ti
 	return clientMessageID, err
 }
 
-func (c *Client) SendMessageWithID(ctx context.Context, threadID string, text string, fromUserID string, clientMessageID string) (int, error) {
-	return c.sendHTMLMessageWithID(ctx, threadID, formatHTMLContent(text), fromUserID, clientMessageID)
+func (c *Client) SendMessageWithID(ctx context.Context, threadID string, body MessageBody, fromUserID string, clientMessageID string) (int, error) {
+	return c.sendRichTextMessageWithID(ctx, threadID, body.HTML, body.properties(), fromUserID, clientMessageID, false)
+}
+
+// SendReplyWithID sends body prefixed with the Teams quote markup for the replied-to message.
+func (c *Client) SendReplyWithID(ctx context.Context, threadID string, body MessageBody, quote ReplyQuote, fromUserID string, clientMessageID string) (int, error) {
+	return c.sendRichTextMessageWithID(ctx, threadID, formatReplyQuote(quote)+body.HTML, body.properties(), fromUserID, clientMessageID, false)
 }
 
 func (c *Client) SendGIFWithID(ctx context.Context, threadID string, gifURL string, title string, fromUserID string, clientMessageID string) (int, error) {
-	return c.sendHTMLMessageWithID(ctx, threadID, formatGIFContent(gifURL, title), fromUserID, clientMessageID)
+	return c.sendRichTextMessageWithID(ctx, threadID, formatGIFContent(gifURL, title), nil, fromUserID, clientMessageID, false)
+}
+
+// SendAMSImageWithID posts an image previously uploaded with UploadAMSImage inline,
+// followed by an optional caption.
+func (c *Client) SendAMSImageWithID(ctx context.Context, threadID string, image AMSImage, caption MessageBody, fromUserID string, clientMessageID string) (int, error) {
+	if strings.TrimSpace(image.ObjectID) == "" || strings.TrimSpace(image.ViewURL) == "" {
+		return 0, errors.New("missing AMS image")
+	}
+	return c.sendRichTextMessageWithID(ctx, threadID, formatAMSImageContent(image)+caption.HTML, caption.properties(), fromUserID, clientMessageID, false)
 }
 
-func (c *Client) sendHTMLMessageWithID(ctx context.Context, threadID string, htmlContent string, fromUserID string, clientMessageID string) (int, error) {
-	return c.sendRichTextMessageWithID(ctx, threadID, htmlContent, "", fromUserID, clientMessageID, false)
+// SendAudioMessageWithID posts a voice clip previously uploaded with UploadAMSAudio.
+func (c *Client) SendAudioMessageWithID(ctx context.Context, threadID string, audio AMSAudio, fromUserID string, clientMessageID string) (int, error) {
+	if strings.TrimSpace(audio.ObjectID) == "" || strings.TrimSpace(audio.ObjectURL) == "" {
+		return 0, errors.New("missing AMS audio")
+	}
+	return c.sendMessageWithType(ctx, threadID, model.AudioMessageType, formatAudioMessageContent(audio), nil, fromUserID, clientMessageID, false)
+}
+
+// SendLocationWithID posts a RichText/Location message, which Teams renders as a
+// Bing Maps card.
+func (c *Client) SendLocationWithID(ctx context.Context, threadID string, location model.Location, fromUserID string, clientMessageID string) (int, error) {
+	return c.sendMessageWithType(ctx, threadID, model.LocationMessageType, formatLocationContent(location, time.Now()), nil, fromUserID, clientMessageID, false)
 }
 
 func (c *Client) SendAttachmentMessageWithID(ctx context.Context, threadID string, htmlContent string, filesProperty string, fromUserID string, clientMessageID string) (int, error) {
 	if strings.TrimSpace(filesProperty) == "" {
 		return 0, errors.New("missing files property")
 	}
-	return c.sendRichTextMessageWithID(ctx, threadID, htmlContent, filesProperty, fromUserID, clientMessageID, true)
+	properties := map[string]string{"files": filesProperty}
+	return c.sendRichTextMessageWithID(ctx, threadID, htmlContent, properties, fromUserID, clientMessageID, true)
 }
 
-func (c *Client) sendRichTextMessageWithID(ctx context.Context, threadID string, htmlContent string, filesProperty string, fromUserID string, clientMessageID string, allowEmptyContent bool) (int, error) {
+// sendRichTextMessageWithID posts a RichText/Html message. Each entry in properties is
+// sent as-is, so JSON-valued properties like files and mentions must already be encoded.
+func (c *Client) sendRichTextMessageWithID(ctx context.Context, threadID string, htmlContent string, properties map[string]string, fromUserID string, clientMessageID string, allowEmptyContent bool) (int, error) {
+	return c.sendMessageWithType(ctx, threadID, "RichText/Html", htmlContent, properties, fromUserID, clientMessageID, allowEmptyContent)
+}
+
+func (c *Client) sendMessageWithType(ctx context.Context, threadID string, messageType string, htmlContent string, properties map[string]string, fromUserID string, clientMessageID string, allowEmptyContent bool) (int, error) {
 	if c == nil || c.HTTP == nil {
 		return 0, ErrMissingHTTPClient
 	}
@@ -210,7 +283,7 @@ func (c *Client) sendRichTextMessageWithID(ctx context.Context, threadID string,
 		"type":                "Message",
 		"conversationid":      threadID,
 		"content":             htmlContent,
-		"messagetype":         "RichText/Html",
+		"messagetype":         messageType,
 		"contenttype":         "Text",
 		"clientmessageid":     clientMessageID,
 		"composetime":         now,
@@ -218,10 +291,8 @@ func (c *Client) sendRichTextMessageWithID(ctx context.Context, threadID string,
 		"from":                fromUserID,
 		"fromUserId":          fromUserID,
 	}
-	if strings.TrimSpace(filesProperty) != "" {
-		payload["properties"] = map[string]string{
-			"files": filesProperty,
-		}
+	if len(properties) > 0 {
+		payload["properties"] = properties
 	}
 	body, err := json.Marshal(payload)
 	if err != nil {
@@ -277,12 +348,266 @@ func (c *Client) sendRichTextMessageWithID(ctx context.Context, threadID string,
 	return resp.StatusCode, nil
 }
 
+func (c *Client) EditMessage(ctx context.Context, threadID string, teamsMessageID string, body MessageBody, fromUserID string) (int, error) {
+	return c.editRichTextMessage(ctx, threadID, teamsMessageID, body.HTML, body.properties(), fromUserID)
+}
+
+func (c *Client) EditReplyMessage(ctx context.Context, threadID string, teamsMessageID string, body MessageBody, quote ReplyQuote, fromUserID string) (int, error) {
+	return c.editRichTextMessage(ctx, threadID, teamsMessageID, formatReplyQuote(quote)+body.HTML, body.properties(), fromUserID)
+}
+
+func (c *Client) editRichTextMessage(ctx context.Context, threadID string, teamsMessageID string, htmlContent string, properties map[string]string, fromUserID string) (int, error) {
+	if c == nil || c.HTTP == nil {
+		return 0, ErrMissingHTTPClient
+	}
+	if c.Token == "" {
+		return 0, ErrMissingToken
+	}
+	threadID = strings.TrimSpace(threadID)
+	if threadID == "" {
+		return 0, errors.New("missing thread id")
+	}
+	teamsMessageID = strings.TrimSpace(teamsMessageID)
+	if teamsMessageID == "" {
+		return 0, errors.New("missing teams message id")
+	}
+	if strings.TrimSpace(htmlContent) == "" {
+		return 0, errors.New("missing message content")
+	}
+	if strings.TrimSpace(fromUserID) == "" {
+		return 0, errors.New("missing from user id")
+	}
+
+	baseURL := c.SendMessagesURL
+	if baseURL == "" {
+		baseURL = defaultSendMessagesURL
+	}
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	endpoint := fmt.Sprintf("%s/%s/messages/%s", baseURL, url.PathEscape(threadID), url.PathEscape(teamsMessageID))
+
+	payload := map[string]interface{}{
+		"type":           "Message",
+		"conversationid": threadID,
+		"content":        htmlContent,
+		"messagetype":    "RichText/Html",
+		"contenttype":    "Text",
+		"from":           fromUserID,
+		"fromUserId":     fromUserID,
+	}
+	if len(properties) > 0 {
+		payload["properties"] = properties
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	req.Header.Set("authentication", "skypetoken="+c.Token)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	c.debugRequest("teams edit message request", endpoint, req)
+
+	ctx = WithRequestMeta(ctx, RequestMeta{
+		ThreadID:       threadID,
+		TeamsMessageID: teamsMessageID,
+		Operation:      "teams edit message",
+	})
+	resp, err := c.executor().Do(ctx, req, classifyTeamsSendResponse)
+	if err != nil {
+		statusCode := 0
+		if resp != nil {
+			statusCode = resp.StatusCode
+			if resp.Body != nil {
+				_ = resp.Body.Close()
+			}
+		}
+		return statusCode, err
+	}
+	defer resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+func (c *Client) DeleteMessage(ctx context.Context, threadID string, teamsMessageID string) (int, error) {
+	if c == nil || c.HTTP == nil {
+		return 0, ErrMissingHTTPClient
+	}
+	if c.Token == "" {
+		return 0, ErrMissingToken
+	}
+	threadID = strings.TrimSpace(threadID)
+	if threadID == "" {
+		return 0, errors.New("missing thread id")
+	}
+	teamsMessageID = strings.TrimSpace(teamsMessageID)
+	if teamsMessageID == "" {
+		return 0, errors.New("missing teams message id")
+	}
+
+	baseURL := c.SendMessagesURL
+	if baseURL == "" {
+		baseURL = defaultSendMessagesURL
+	}
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	endpoint := fmt.Sprintf("%s/%s/messages/%s?behavior=softDelete", baseURL, url.PathEscape(threadID), url.PathEscape(teamsMessageID))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, endpoint, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("authentication", "skypetoken="+c.Token)
+	req.Header.Set("Accept", "application/json")
+	c.debugRequest("teams delete message request", endpoint, req)
+
+	ctx = WithRequestMeta(ctx, RequestMeta{
+		ThreadID:       threadID,
+		TeamsMessageID: teamsMessageID,
+		Operation:      "teams delete message",
+	})
+	resp, err := c.executor().Do(ctx, req, classifyTeamsDeleteResponse)
+	if err != nil {
+		statusCode := 0
+		if resp != nil {
+			statusCode = resp.StatusCode
+			if resp.Body != nil {
+				_ = resp.Body.Close()
+			}
+		}
+		return statusCode, err
+	}
+	defer resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+func classifyTeamsDeleteResponse(resp *http.Response) error {
+	if resp == nil {
+		return errors.New("missing response")
+	}
+	if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
+		return nil
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return RetryableError{
+			Status:     resp.StatusCode,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
+	}
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return RetryableError{Status: resp.StatusCode}
+	}
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+	return DeleteMessageError{
+		Status:      resp.StatusCode,
+		BodySnippet: string(snippet),
+	}
+}
+
+func (c *Client) executor() *TeamsRequestExecutor {
+	executor := c.Executor
+	if executor == nil {
+		executor = &TeamsRequestExecutor{
+			HTTP:        c.HTTP,
+			Log:         zerolog.Nop(),
+			MaxRetries:  4,
+			BaseBackoff: 500 * time.Millisecond,
+			MaxBackoff:  10 * time.Second,
+		}
+		c.Executor = executor
+	}
+	if executor.HTTP == nil {
+		executor.HTTP = c.HTTP
+	}
+	if c.Log != nil {
+		executor.Log = *c.Log
+	}
+	return executor
+}
+
 func formatHTMLContent(text string) string {
-	normalized := strings.ReplaceAll(text, "\r\n", "\n")
-	normalized = strings.ReplaceAll(normalized, "\r", "\n")
-	escaped := html.EscapeString(normalized)
-	escaped = strings.ReplaceAll(escaped, "\n", "<br>")
-	return "<p>" + escaped + "</p>"
+	return "<p>" + escapeHTMLText(normalizeNewlines(text)) + "</p>"
+}
+
+func escapeHTMLText(text string) string {
+	return strings.ReplaceAll(html.EscapeString(text), "\n", "<br>")
+}
+
+func normalizeNewlines(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	return strings.ReplaceAll(text, "\r", "\n")
+}
+
+// ReplyQuote identifies the Teams message a reply quotes.
+type ReplyQuote struct {
+	MessageID  string
+	SenderID   string
+	SenderName string
+	Timestamp  time.Time
+	Preview    string
+}
+
+// formatReplyQuote renders the blockquote Teams clients turn into a native quote card.
+func formatReplyQuote(quote ReplyQuote) string {
+	messageID := strings.TrimSpace(quote.MessageID)
+	if messageID == "" {
+		return ""
+	}
+	senderName := strings.TrimSpace(quote.SenderName)
+	if senderName == "" {
+		senderName = strings.TrimSpace(quote.SenderID)
+	}
+	timestamp := messageID
+	if !quote.Timestamp.IsZero() {
+		timestamp = strconv.FormatInt(quote.Timestamp.UnixMilli(), 10)
+	}
+	var b strings.Builder
+	b.WriteString(`<blockquote itemscope="" itemtype="http://schema.skype.com/Reply" itemid="`)
+	b.WriteString(html.EscapeString(messageID))
+	b.WriteString(`"><strong itemprop="mri" itemid="`)
+	b.WriteString(html.EscapeString(strings.TrimSpace(quote.SenderID)))
+	b.WriteString(`">`)
+	b.WriteString(html.EscapeString(senderName))
+	b.WriteString(`</strong><span itemprop="time" itemid="`)
+	b.WriteString(timestamp)
+	b.WriteString(`"></span><p itemprop="preview">`)
+	b.WriteString(html.EscapeString(strings.TrimSpace(quote.Preview)))
+	b.WriteString(`</p></blockquote>`)
+	return b.String()
+}
+
+// ForwardedBody wraps body in the forward block Teams clients render as a
+// "Forwarded" card crediting the original sender.
+func ForwardedBody(body MessageBody, origin model.MessageForward) MessageBody {
+	senderID := strings.TrimSpace(origin.SenderID)
+	senderName := strings.TrimSpace(origin.SenderName)
+	if senderName == "" {
+		senderName = senderID
+	}
+	var b strings.Builder
+	b.WriteString(`<blockquote itemscope="" itemtype="` + model.ForwardItemType + `"`)
+	if messageID := strings.TrimSpace(origin.MessageID); messageID != "" {
+		b.WriteString(` itemid="` + html.EscapeString(messageID) + `"`)
+	}
+	b.WriteString(`>`)
+	if senderID != "" || senderName != "" {
+		b.WriteString(`<strong itemprop="mri"`)
+		if senderID != "" {
+			b.WriteString(` itemid="` + html.EscapeString(senderID) + `"`)
+		}
+		b.WriteString(`>` + html.EscapeString(senderName) + `</strong>`)
+	}
+	if !origin.Timestamp.IsZero() {
+		b.WriteString(`<span itemprop="time" itemid="` + strconv.FormatInt(origin.Timestamp.UnixMilli(), 10) + `"></span>`)
+	}
+	b.WriteString(body.HTML)
+	b.WriteString(`</blockquote>`)
+	body.HTML = b.String()
+	return body
 }
 
 func formatGIFContent(gifURL string, title string) string {
@@ -295,6 +620,64 @@ func formatGIFContent(gifURL string, title string) string {
 	return `<p>&nbsp;</p><readonly title="` + html.EscapeString(fullLabel) + `" itemtype="http://schema.skype.com/Giphy" contenteditable="false" aria-label="` + html.EscapeString(fullLabel) + `"><img style="height:auto;margin-top:4px;max-width:100%;" alt="` + html.EscapeString(fullLabel) + `" height="250" width="350" src="` + html.EscapeString(gifURL) + `" itemtype="http://schema.skype.com/Giphy"></readonly><p>&nbsp;</p>`
 }
 
+func formatAMSImageContent(image AMSImage) string {
+	var b strings.Builder
+	b.WriteString(`<p><img itemscope="" itemtype="http://schema.skype.com/AMSImage" src="`)
+	b.WriteString(html.EscapeString(strings.TrimSpace(image.ViewURL)))
+	b.WriteString(`" id="x_`)
+	b.WriteString(html.EscapeString(strings.TrimSpace(image.ObjectID)))
+	b.WriteString(`"`)
+	if image.Width > 0 && image.Height > 0 {
+		fmt.Fprintf(&b, ` width="%d" height="%d" style="vertical-align:bottom; width: %dpx; height: %dpx;"`, image.Width, image.Height, image.Width, image.Height)
+	}
+	b.WriteString(`></p>`)
+	return b.String()
+}
+
+func formatAudioMessageContent(audio AMSAudio) string {
+	objectURL := html.EscapeString(strings.TrimSpace(audio.ObjectURL))
+	filename := strings.TrimSpace(audio.Filename)
+	if filename == "" {
+		filename = "voice.ogg"
+	}
+	var b strings.Builder
+	b.WriteString(`<URIObject type="Audio.1/Message.1" uri="` + objectURL + `" url_thumbnail="` + objectURL + `/views/thumbnail" ams_id="` + html.EscapeString(strings.TrimSpace(audio.ObjectID)) + `">`)
+	b.WriteString(`To hear this voice message, click here: <a href="` + objectURL + `/views/audio">` + objectURL + `/views/audio</a>`)
+	b.WriteString(`<OriginalName v="` + html.EscapeString(filename) + `"></OriginalName>`)
+	if audio.Size > 0 {
+		fmt.Fprintf(&b, `<FileSize v="%d"></FileSize>`, audio.Size)
+	}
+	if audio.Duration > 0 {
+		fmt.Fprintf(&b, `<duration_ms>%d</duration_ms>`, audio.Duration.Milliseconds())
+	}
+	b.WriteString(`</URIObject>`)
+	return b.String()
+}
+
+// formatLocationContent renders a location the way Teams clients send it: the
+// coordinates as integer microdegrees, with a Bing Maps link in degrees.
+func formatLocationContent(location model.Location, ts time.Time) string {
+	latitude := strconv.FormatFloat(location.Latitude, 'f', 6, 64)
+	longitude := strconv.FormatFloat(location.Longitude, 'f', 6, 64)
+	mapURL := "https://www.bing.com/maps?cp=" + latitude + "~" + longitude + "&lvl=16&sp=point." + latitude + "_" + longitude
+	label := location.Description()
+	if label == "" {
+		label = latitude + ", " + longitude
+	}
+	var b strings.Builder
+	b.WriteString(`<location isUserLocation="0" latitude="` + model.MicrodegreeString(location.Latitude) +
+		`" longitude="` + model.MicrodegreeString(location.Longitude) + `"`)
+	fmt.Fprintf(&b, ` timeStamp="%d"`, ts.UnixMilli())
+	if name := strings.TrimSpace(location.Name); name != "" {
+		b.WriteString(` pointOfInterest="` + html.EscapeString(name) + `"`)
+	}
+	if address := strings.TrimSpace(location.Address); address != "" {
+		b.WriteString(` address="` + html.EscapeString(address) + `"`)
+	}
+	b.WriteString(`><a href="` + html.EscapeString(mapURL) + `">` + html.EscapeString(label) + `</a></location>`)
+	return b.String()
+}
+
 func classifyTeamsSendResponse(resp *http.Response) error {
 	if resp == nil {
 		return errors.New("missing response")
@@ -328,6 +328,76 @@ func TestListMessagesEmotionsParsing(t *testing.T) {
 	}
 }
 
+func TestListMessagesEditAndDeleteParsing(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"messages":[` +
+			`{"id":"m1","sequenceId":"1","content":{"text":"edited"},"properties":{"edittime":"1700000000123"}},` +
+			`{"id":"m2","sequenceId":"2","content":{"text":"fixed"},"skypeeditedid":"c1","properties":{"edittime":1700000000456}},` +
+			`{"id":"m3","sequenceId":"3","content":{"text":"plain"}},` +
+			`{"id":"m4","sequenceId":"4","content":"","properties":{"deletetime":"1700000000789"}}` +
+			`]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.Client())
+	client.MessagesURL = server.URL + "/conversations"
+	client.Token = "token123"
+
+	msgs, err := client.ListMessages(context.Background(), "@oneToOne.skype", "")
+	if err != nil {
+		t.Fatalf("ListMessages failed: %v", err)
+	}
+	if len(msgs) != 4 {
+		t.Fatalf("unexpected messages length: %d", len(msgs))
+	}
+	if !msgs[0].IsEdited() || msgs[0].EditTime.UnixMilli() != 1700000000123 {
+		t.Fatalf("unexpected edit time: %s", msgs[0].EditTime)
+	}
+	if msgs[1].EditedMessageID != "c1" || msgs[1].EditTime.UnixMilli() != 1700000000456 {
+		t.Fatalf("unexpected skype edit fields: %#v", msgs[1])
+	}
+	if msgs[2].IsEdited() || msgs[2].EditedMessageID != "" || msgs[2].IsDeleted() {
+		t.Fatalf("expected unedited message: %#v", msgs[2])
+	}
+	if !msgs[3].IsDeleted() || msgs[3].DeleteTime.UnixMilli() != 1700000000789 {
+		t.Fatalf("expected deleted message: %#v", msgs[3])
+	}
+}
+
+func TestListMessagesMessageTypeParsing(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"messages":[` +
+			`{"id":"m1","sequenceId":"1","messagetype":"RichText/Html","content":"<p>hi</p>"},` +
+			`{"id":"m2","sequenceId":"2","messagetype":"ThreadActivity/AddMember","content":"<addmember/>"},` +
+			`{"id":"m3","sequenceId":"3","content":"plain"}` +
+			`]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.Client())
+	client.MessagesURL = server.URL + "/conversations"
+	client.Token = "token123"
+
+	msgs, err := client.ListMessages(context.Background(), "@oneToOne.skype", "")
+	if err != nil {
+		t.Fatalf("ListMessages failed: %v", err)
+	}
+	if len(msgs) != 3 {
+		t.Fatalf("unexpected messages length: %d", len(msgs))
+	}
+	if msgs[0].MessageType != "RichText/Html" || msgs[0].Kind != model.MessageKindText {
+		t.Fatalf("unexpected kind for html message: %#v", msgs[0])
+	}
+	if msgs[1].MessageType != "ThreadActivity/AddMember" || msgs[1].Kind != model.MessageKindThreadActivity {
+		t.Fatalf("unexpected kind for thread activity: %#v", msgs[1])
+	}
+	if msgs[2].Kind != model.MessageKindText {
+		t.Fatalf("expected missing messagetype to be text: %#v", msgs[2])
+	}
+}
+
 func TestListMessagesFilesParsing(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
@@ -515,6 +585,140 @@ func TestSendGIFWithIDSuccess(t *testing.T) {
 	}
 }
 
+func TestEditMessageSuccess(t *testing.T) {
+	var gotMethod string
+	var gotPath string
+	var payload map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		gotPath = r.URL.EscapedPath()
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.Client())
+	client.SendMessagesURL = server.URL + "/conversations"
+	client.Token = "token123"
+
+	statusCode, err := client.EditMessage(context.Background(), "19:abc@thread.v2", "1700000000000", PlainTextBody("fixed <typo>", nil), "8:live:me")
+	if err != nil {
+		t.Fatalf("EditMessage failed: %v", err)
+	}
+	if statusCode != http.StatusOK {
+		t.Fatalf("unexpected status code: %d", statusCode)
+	}
+	if gotMethod != http.MethodPut {
+		t.Fatalf("unexpected method: %s", gotMethod)
+	}
+	if gotPath != "/conversations/19:abc@thread.v2/messages/1700000000000" && gotPath != "/conversations/19%3Aabc@thread.v2/messages/1700000000000" {
+		t.Fatalf("unexpected path: %q", gotPath)
+	}
+	if payload["content"] != "<p>fixed &lt;typo&gt;</p>" {
+		t.Fatalf("unexpected content: %q", payload["content"])
+	}
+	if payload["messagetype"] != "RichText/Html" || payload["from"] != "8:live:me" {
+		t.Fatalf("unexpected payload: %#v", payload)
+	}
+}
+
+func TestEditMessageMissingMessageID(t *testing.T) {
+	client := NewClient(http.DefaultClient)
+	client.Token = "token123"
+	if _, err := client.EditMessage(context.Background(), "19:abc@thread.v2", " ", PlainTextBody("text", nil), "8:live:me"); err == nil {
+		t.Fatalf("expected error for missing message id")
+	}
+}
+
+func TestDeleteMessageSuccess(t *testing.T) {
+	var gotMethod string
+	var gotPath string
+	var gotQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		gotPath = r.URL.EscapedPath()
+		gotQuery = r.URL.RawQuery
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.Client())
+	client.SendMessagesURL = server.URL + "/conversations"
+	client.Token = "token123"
+
+	statusCode, err := client.DeleteMessage(context.Background(), "19:abc@thread.v2", "1700000000000")
+	if err != nil {
+		t.Fatalf("DeleteMessage failed: %v", err)
+	}
+	if statusCode != http.StatusOK {
+		t.Fatalf("unexpected status code: %d", statusCode)
+	}
+	if gotMethod != http.MethodDelete {
+		t.Fatalf("unexpected method: %s", gotMethod)
+	}
+	if gotPath != "/conversations/19:abc@thread.v2/messages/1700000000000" && gotPath != "/conversations/19%3Aabc@thread.v2/messages/1700000000000" {
+		t.Fatalf("unexpected path: %q", gotPath)
+	}
+	if gotQuery != "behavior=softDelete" {
+		t.Fatalf("unexpected query: %q", gotQuery)
+	}
+}
+
+func TestDeleteMessageNon2xx(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte("nope"))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.Client())
+	client.SendMessagesURL = server.URL + "/conversations"
+	client.Token = "token123"
+
+	_, err := client.DeleteMessage(context.Background(), "19:abc@thread.v2", "1700000000000")
+	var deleteErr DeleteMessageError
+	if !errors.As(err, &deleteErr) {
+		t.Fatalf("expected DeleteMessageError, got %T", err)
+	}
+	if deleteErr.Status != http.StatusForbidden || deleteErr.BodySnippet != "nope" {
+		t.Fatalf("unexpected error: %#v", deleteErr)
+	}
+}
+
+func TestDeleteMessageRetriesAfter5xx(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.Client())
+	client.SendMessagesURL = server.URL + "/conversations"
+	client.Token = "token123"
+	client.Executor = &TeamsRequestExecutor{
+		HTTP:        server.Client(),
+		MaxRetries:  1,
+		BaseBackoff: time.Millisecond,
+		MaxBackoff:  time.Millisecond,
+		sleep:       func(ctx context.Context, d time.Duration) error { return nil },
+		jitter:      func(d time.Duration) time.Duration { return d },
+	}
+
+	if _, err := client.DeleteMessage(context.Background(), "19:abc@thread.v2", "1700000000000"); err != nil {
+		t.Fatalf("DeleteMessage failed: %v", err)
+	}
+	if atomic.LoadInt32(&calls) != 2 {
+		t.Fatalf("expected 2 calls, got %d", calls)
+	}
+}
+
 func TestSendMessageNon2xx(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusBadRequest)
@@ -576,7 +780,7 @@ func TestSendMessageRetriesAfter429(t *testing.T) {
 
 	threadID := "@19:abc@thread.v2"
 	clientMessageID := "123456"
-	statusCode, err := client.SendMessageWithID(context.Background(), threadID, "hello", "8:live:me", clientMessageID)
+	statusCode, err := client.SendMessageWithID(context.Background(), threadID, PlainTextBody("hello", nil), "8:live:me", clientMessageID)
 	if err != nil {
 		t.Fatalf("SendMessageWithID failed: %v", err)
 	}
@@ -613,7 +817,7 @@ func TestSendMessageNoRetryOn400(t *testing.T) {
 		jitter:     func(d time.Duration) time.Duration { return d },
 	}
 
-	_, err := client.SendMessageWithID(context.Background(), "@19:abc@thread.v2", "hello", "8:live:me", "999")
+	_, err := client.SendMessageWithID(context.Background(), "@19:abc@thread.v2", PlainTextBody("hello", nil), "8:live:me", "999")
 	if err == nil {
 		t.Fatalf("expected error")
 	}
@@ -726,3 +930,108 @@ func TestCompareSequenceIDFallback(t *testing.T) {
 		t.Fatalf("expected lexicographic comparison when parse fails")
 	}
 }
+
+func TestSendReplyWithIDPrependsQuote(t *testing.T) {
+	var payload map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.Client())
+	client.SendMessagesURL = server.URL + "/conversations"
+	client.Token = "token123"
+
+	quote := ReplyQuote{
+		MessageID:  "1700000000000",
+		SenderID:   "8:live:alice",
+		SenderName: "Alice <A>",
+		Timestamp:  time.UnixMilli(1700000000123),
+		Preview:    "original",
+	}
+	if _, err := client.SendReplyWithID(context.Background(), "19:abc@thread.v2", PlainTextBody("reply", nil), quote, "8:live:me", "1"); err != nil {
+		t.Fatalf("SendReplyWithID failed: %v", err)
+	}
+	want := `<blockquote itemscope="" itemtype="http://schema.skype.com/Reply" itemid="1700000000000">` +
+		`<strong itemprop="mri" itemid="8:live:alice">Alice &lt;A&gt;</strong><span itemprop="time" itemid="1700000000123"></span>` +
+		`<p itemprop="preview">original</p></blockquote><p>reply</p>`
+	if payload["content"] != want {
+		t.Fatalf("unexpected content:\nwant: %s\ngot:  %s", want, payload["content"])
+	}
+}
+
+func TestFormatReplyQuoteWithoutMessageID(t *testing.T) {
+	if got := formatReplyQuote(ReplyQuote{SenderID: "8:live:alice"}); got != "" {
+		t.Fatalf("expected empty quote, got %q", got)
+	}
+}
+
+func TestSendLocationWithIDPayload(t *testing.T) {
+	var payload struct {
+		Content     string `json:"content"`
+		MessageType string `json:"messagetype"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	c := NewClient(server.Client())
+	c.SendMessagesURL = server.URL + "/conversations"
+	c.Token = "token123"
+
+	location := model.Location{Latitude: 52.2297, Longitude: -21.0122, Name: "Café <Nowy>"}
+	if _, err := c.SendLocationWithID(context.Background(), "19:abc@thread.v2", location, "8:live:me", "1"); err != nil {
+		t.Fatalf("SendLocationWithID failed: %v", err)
+	}
+	if payload.MessageType != model.LocationMessageType {
+		t.Fatalf("unexpected messagetype: %q", payload.MessageType)
+	}
+	if !strings.Contains(payload.Content, `href="https://www.bing.com/maps?cp=52.229700~-21.012200&amp;lvl=16&amp;sp=point.52.229700_-21.012200"`) {
+		t.Fatalf("missing map link: %s", payload.Content)
+	}
+
+	if !strings.HasPrefix(payload.Content, `<location isUserLocation="0" latitude="52229700" longitude="-21012200" timeStamp="`) {
+		t.Fatalf("expected microdegree coordinates: %s", payload.Content)
+	}
+
+	// The outbound location must be recognized by the inbound parser.
+	content, _ := json.Marshal(payload.Content)
+	parsed := model.ParseLocation(payload.MessageType, content)
+	if parsed == nil || *parsed != location {
+		t.Fatalf("unexpected parsed location: %#v", parsed)
+	}
+}
+
+// teamsLocationPayload is a location message in the shape Teams and Skype
+// clients send, with microdegree coordinates and extra locale attributes.
+const teamsLocationPayload = `<location isUserLocation="1" latitude="51503396" longitude="-127699" timeStamp="1502386018000" ` +
+	`timezone="Europe/London" locale="en-GB" language="en" address="Westminster, London SW1A 2AA, United Kingdom" ` +
+	`addressFriendlyName="Westminster" shortAddress="Westminster, London" userMri="8:live:alice">` +
+	`<a href="https://www.bing.com/maps/?cp=51.503396~-0.127699&amp;lvl=16&amp;sp=Point.51.503396_-0.127699_Location_">` +
+	`Westminster, London SW1A 2AA, United Kingdom</a></location>`
+
+func TestFormatLocationContentMatchesClientPayload(t *testing.T) {
+	content, _ := json.Marshal(teamsLocationPayload)
+	received := model.ParseLocation(model.LocationMessageType, content)
+	if received == nil || received.Latitude != 51.503396 || received.Longitude != -0.127699 {
+		t.Fatalf("unexpected parsed client location: %#v", received)
+	}
+
+	formatted := formatLocationContent(*received, time.UnixMilli(1502386018000))
+	if !strings.Contains(formatted, `latitude="51503396" longitude="-127699" timeStamp="1502386018000"`) {
+		t.Fatalf("outbound coordinates don't match the client format: %s", formatted)
+	}
+	content, _ = json.Marshal(formatted)
+	if sent := model.ParseLocation(model.LocationMessageType, content); sent == nil || *sent != *received {
+		t.Fatalf("unexpected round-tripped location: %#v", sent)
+	}
+}
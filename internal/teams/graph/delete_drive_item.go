@@ -0,0 +1,69 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	teamsclient "go.mau.fi/mautrix-teams/internal/teams/client"
+)
+
+type GraphDeleteDriveItemError struct {
+	Status      int
+	BodySnippet string
+}
+
+func (e GraphDeleteDriveItemError) Error() string {
+	return "graph delete drive item request failed"
+}
+
+// DeleteDriveItem removes an item from the current user's OneDrive. Items that
+// are already gone are treated as deleted.
+func (c *GraphClient) DeleteDriveItem(ctx context.Context, driveItemID string) error {
+	if c == nil || c.HTTP == nil {
+		return ErrMissingGraphHTTPClient
+	}
+	if strings.TrimSpace(c.AccessToken) == "" {
+		return ErrMissingGraphAccessToken
+	}
+	driveItemID = strings.TrimSpace(driveItemID)
+	if driveItemID == "" {
+		return ErrEmptyDriveItemID
+	}
+
+	endpoint := "https://graph.microsoft.com/v1.0/me/drive/items/" + url.PathEscape(driveItemID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(c.AccessToken))
+
+	resp, err := c.executor().Do(ctx, req, classifyDeleteDriveItemResponse)
+	if resp != nil && resp.Body != nil {
+		_ = resp.Body.Close()
+	}
+	return err
+}
+
+func classifyDeleteDriveItemResponse(resp *http.Response) error {
+	if resp == nil {
+		return errors.New("missing response")
+	}
+	if (resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices) || resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return teamsclient.RetryableError{
+			Status:     resp.StatusCode,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
+	}
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return teamsclient.RetryableError{Status: resp.StatusCode}
+	}
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, maxUploadErrorBytes))
+	return GraphDeleteDriveItemError{Status: resp.StatusCode, BodySnippet: string(snippet)}
+}
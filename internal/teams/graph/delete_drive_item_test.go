@@ -0,0 +1,65 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	teamsclient "go.mau.fi/mautrix-teams/internal/teams/client"
+)
+
+func TestDeleteDriveItemSuccess(t *testing.T) {
+	token := "graph-token"
+	httpClient := &http.Client{
+		Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			if r.Method != http.MethodDelete {
+				t.Fatalf("unexpected method: %s", r.Method)
+			}
+			if r.URL.EscapedPath() != "/v1.0/me/drive/items/CID%21sabc123" {
+				t.Fatalf("unexpected path: %s", r.URL.EscapedPath())
+			}
+			if r.Header.Get("Authorization") != "Bearer "+token {
+				t.Fatalf("unexpected authorization header: %s", r.Header.Get("Authorization"))
+			}
+			return &http.Response{
+				StatusCode: http.StatusNoContent,
+				Body:       io.NopCloser(strings.NewReader("")),
+				Header:     make(http.Header),
+				Request:    r,
+			}, nil
+		}),
+	}
+
+	client := NewClient(httpClient)
+	client.AccessToken = token
+	client.Executor = &teamsclient.TeamsRequestExecutor{HTTP: httpClient, MaxRetries: 0}
+
+	if err := client.DeleteDriveItem(context.Background(), "CID!sabc123"); err != nil {
+		t.Fatalf("DeleteDriveItem failed: %v", err)
+	}
+}
+
+func TestClassifyDeleteDriveItemResponse(t *testing.T) {
+	notFound := &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(strings.NewReader(""))}
+	if err := classifyDeleteDriveItemResponse(notFound); err != nil {
+		t.Fatalf("expected missing item to be treated as deleted, got %v", err)
+	}
+
+	forbidden := &http.Response{StatusCode: http.StatusForbidden, Body: io.NopCloser(strings.NewReader("denied"))}
+	var deleteErr GraphDeleteDriveItemError
+	if err := classifyDeleteDriveItemResponse(forbidden); !errors.As(err, &deleteErr) {
+		t.Fatalf("expected GraphDeleteDriveItemError, got %T", err)
+	}
+	if deleteErr.Status != http.StatusForbidden || deleteErr.BodySnippet != "denied" {
+		t.Fatalf("unexpected error: %#v", deleteErr)
+	}
+
+	unavailable := &http.Response{StatusCode: http.StatusServiceUnavailable, Body: io.NopCloser(strings.NewReader(""))}
+	var retryable teamsclient.RetryableError
+	if err := classifyDeleteDriveItemResponse(unavailable); !errors.As(err, &retryable) {
+		t.Fatalf("expected RetryableError, got %T", err)
+	}
+}
@@ -0,0 +1,68 @@
+package model
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AudioMessageType is the messagetype of a Teams voice clip.
+const AudioMessageType = "RichText/Media_AudioMsg"
+
+// AudioMessage is a voice clip stored in AMS.
+type AudioMessage struct {
+	ObjectID string
+	// URL is the audio view of the AMS object, downloadable with the skypetoken.
+	URL      string
+	Filename string
+	Size     int64
+	Duration time.Duration
+}
+
+type audioURIObjectXML struct {
+	Type         string `xml:"type,attr"`
+	URI          string `xml:"uri,attr"`
+	AMSID        string `xml:"ams_id,attr"`
+	OriginalName struct {
+		V string `xml:"v,attr"`
+	} `xml:"OriginalName"`
+	FileSize struct {
+		V string `xml:"v,attr"`
+	} `xml:"FileSize"`
+	DurationMS string `xml:"duration_ms"`
+}
+
+// ParseAudioMessage parses the URIObject content of a RichText/Media_AudioMsg
+// message. It returns nil for other message types or when the object isn't in AMS.
+func ParseAudioMessage(messageType string, content json.RawMessage) *AudioMessage {
+	if !strings.EqualFold(strings.TrimSpace(messageType), AudioMessageType) {
+		return nil
+	}
+	var raw string
+	if err := json.Unmarshal(content, &raw); err != nil || strings.TrimSpace(raw) == "" {
+		return nil
+	}
+	var parsed audioURIObjectXML
+	if err := xml.Unmarshal([]byte(raw), &parsed); err != nil {
+		return nil
+	}
+	uri := strings.TrimSuffix(strings.TrimSpace(parsed.URI), "/")
+	objectID := AMSObjectID(uri)
+	if objectID == "" {
+		return nil
+	}
+	audio := &AudioMessage{
+		ObjectID: objectID,
+		URL:      uri + "/views/audio",
+		Filename: strings.TrimSpace(parsed.OriginalName.V),
+	}
+	if size, err := strconv.ParseInt(strings.TrimSpace(parsed.FileSize.V), 10, 64); err == nil && size > 0 {
+		audio.Size = size
+	}
+	if ms, err := strconv.ParseInt(strings.TrimSpace(parsed.DurationMS), 10, 64); err == nil && ms > 0 {
+		audio.Duration = time.Duration(ms) * time.Millisecond
+	}
+	return audio
+}
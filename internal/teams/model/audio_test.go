@@ -0,0 +1,38 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestParseAudioMessage(t *testing.T) {
+	raw := `<URIObject type="Audio.1/Message.1" uri="https://api.asm.skype.com/v1/objects/0-weu-d1-abc" ` +
+		`url_thumbnail="https://api.asm.skype.com/v1/objects/0-weu-d1-abc/views/thumbnail" ams_id="0-weu-d1-abc">` +
+		`To hear this voice message, click here: <a href="https://login.skype.com/login/sso?go=webclient.xmm&amp;docid=0-weu-d1-abc">link</a>` +
+		`<OriginalName v="voice.m4a"></OriginalName><FileSize v="12345"></FileSize><duration_ms>2900</duration_ms></URIObject>`
+	content, _ := json.Marshal(raw)
+
+	audio := ParseAudioMessage("RichText/Media_AudioMsg", content)
+	if audio == nil {
+		t.Fatal("expected audio message")
+	}
+	want := AudioMessage{
+		ObjectID: "0-weu-d1-abc",
+		URL:      "https://api.asm.skype.com/v1/objects/0-weu-d1-abc/views/audio",
+		Filename: "voice.m4a",
+		Size:     12345,
+		Duration: 2900 * time.Millisecond,
+	}
+	if *audio != want {
+		t.Fatalf("unexpected audio:\nwant: %#v\ngot:  %#v", want, *audio)
+	}
+
+	if ParseAudioMessage("RichText/Media_GenericFile", content) != nil {
+		t.Fatal("expected other media types to be ignored")
+	}
+	notAMS, _ := json.Marshal(`<URIObject uri="https://example.com/v1/objects/abc"></URIObject>`)
+	if ParseAudioMessage("RichText/Media_AudioMsg", notAMS) != nil {
+		t.Fatal("expected non-AMS objects to be ignored")
+	}
+}
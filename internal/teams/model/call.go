@@ -0,0 +1,141 @@
+package model
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type CallEventType string
+
+const (
+	CallStarted CallEventType = "started"
+	CallEnded   CallEventType = "ended"
+	CallMissed  CallEventType = "missed"
+)
+
+type CallParticipant struct {
+	ID          string
+	DisplayName string
+	Duration    time.Duration
+}
+
+// CallEvent is a parsed Event/Call message. Every event of the same call shares
+// CallID, so the start and end can be rendered as one Matrix event.
+type CallEvent struct {
+	CallID       string
+	Type         CallEventType
+	Participants []CallParticipant
+}
+
+type callEventXML struct {
+	Ended  *struct{} `xml:"ended"`
+	Missed *struct{} `xml:"missed"`
+	CallID string    `xml:"callId"`
+	Parts  struct {
+		Type   string `xml:"type,attr"`
+		CallID string `xml:"callId,attr"`
+		Part   []struct {
+			Identity string `xml:"identity,attr"`
+			Name     string `xml:"name"`
+			Duration string `xml:"duration"`
+		} `xml:"part"`
+	} `xml:"partlist"`
+}
+
+// ParseCallEvent parses the XML content of an Event/Call message, which is a
+// <partlist> optionally preceded by <ended/>. It returns nil if the content
+// isn't a call event.
+func ParseCallEvent(content json.RawMessage) *CallEvent {
+	var raw string
+	if err := json.Unmarshal(content, &raw); err != nil || strings.TrimSpace(raw) == "" {
+		return nil
+	}
+	// The content has several top-level elements, so give it a single root.
+	var parsed callEventXML
+	if err := xml.Unmarshal([]byte("<call>"+raw+"</call>"), &parsed); err != nil {
+		return nil
+	}
+
+	event := &CallEvent{
+		CallID: strings.TrimSpace(parsed.Parts.CallID),
+	}
+	if event.CallID == "" {
+		event.CallID = strings.TrimSpace(parsed.CallID)
+	}
+	switch {
+	case parsed.Ended != nil:
+		event.Type = CallEnded
+	case parsed.Missed != nil:
+		event.Type = CallMissed
+	default:
+		switch CallEventType(strings.ToLower(strings.TrimSpace(parsed.Parts.Type))) {
+		case CallEnded:
+			event.Type = CallEnded
+		case CallMissed:
+			event.Type = CallMissed
+		case CallStarted, "":
+			event.Type = CallStarted
+		default:
+			return nil
+		}
+	}
+	for _, part := range parsed.Parts.Part {
+		participant := CallParticipant{
+			ID:          NormalizeTeamsUserID(part.Identity),
+			DisplayName: strings.TrimSpace(part.Name),
+		}
+		if seconds, err := strconv.ParseFloat(strings.TrimSpace(part.Duration), 64); err == nil && seconds > 0 {
+			participant.Duration = time.Duration(seconds * float64(time.Second))
+		}
+		event.Participants = append(event.Participants, participant)
+	}
+	return event
+}
+
+// Duration is the longest participant duration, which is how long the call lasted.
+func (e *CallEvent) Duration() time.Duration {
+	var longest time.Duration
+	for _, participant := range e.Participants {
+		longest = max(longest, participant.Duration)
+	}
+	return longest
+}
+
+// Notice renders the event as a short sentence. callerName names whoever
+// placed the call and is used when the participant list has no name.
+func (e *CallEvent) Notice(callerName string) string {
+	callerName = strings.TrimSpace(callerName)
+	if callerName == "" && len(e.Participants) > 0 {
+		callerName = e.Participants[0].DisplayName
+	}
+	switch e.Type {
+	case CallMissed:
+		if callerName == "" {
+			return "Missed call"
+		}
+		return "Missed call from " + callerName
+	case CallEnded:
+		if duration := e.Duration(); duration > 0 {
+			return "Call ended, " + formatCallDuration(duration) + " long"
+		}
+		return "Call ended"
+	default:
+		if callerName == "" {
+			return "Call started"
+		}
+		return "Call started by " + callerName
+	}
+}
+
+func formatCallDuration(duration time.Duration) string {
+	total := int(duration.Round(time.Second) / time.Second)
+	hours, minutes, seconds := total/3600, total/60%60, total%60
+	if hours > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", hours, minutes, seconds)
+	}
+	return fmt.Sprintf("%d:%02d", minutes, seconds)
+}
@@ -0,0 +1,84 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestParseCallEvent(t *testing.T) {
+	cases := []struct {
+		name     string
+		raw      string
+		wantType CallEventType
+		wantID   string
+		notice   string
+	}{
+		{
+			name:     "started",
+			raw:      `<partlist type="started" alt="" callId="call-1"><part identity="8:live:alice"><name>Alice</name></part></partlist>`,
+			wantType: CallStarted,
+			wantID:   "call-1",
+			notice:   "Call started by Alice",
+		},
+		{
+			name: "ended",
+			raw: `<ended/><partlist count="2" callId="call-1"><part identity="8:live:alice"><name>Alice</name><duration>723.4</duration></part>` +
+				`<part identity="8:live:bob"><name>Bob</name><duration>700</duration></part></partlist>`,
+			wantType: CallEnded,
+			wantID:   "call-1",
+			notice:   "Call ended, 12:03 long",
+		},
+		{
+			name:     "ended over an hour",
+			raw:      `<callId>call-2</callId><partlist type="ended" alt=""><part identity="8:live:alice"><duration>3725</duration></part></partlist>`,
+			wantType: CallEnded,
+			wantID:   "call-2",
+			notice:   "Call ended, 1:02:05 long",
+		},
+		{
+			name:     "missed",
+			raw:      `<partlist type="missed" alt="" callId="call-3"><part identity="8:live:alice"><name>Alice</name></part></partlist>`,
+			wantType: CallMissed,
+			wantID:   "call-3",
+			notice:   "Missed call from Alice",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			content, _ := json.Marshal(tc.raw)
+			call := ParseCallEvent(content)
+			if call == nil {
+				t.Fatal("expected call event")
+			}
+			if call.Type != tc.wantType {
+				t.Fatalf("unexpected type: %q", call.Type)
+			}
+			if tc.wantID != "" && call.CallID != tc.wantID {
+				t.Fatalf("unexpected call id: %q", call.CallID)
+			}
+			if got := call.Notice(""); got != tc.notice {
+				t.Fatalf("unexpected notice: %q", got)
+			}
+		})
+	}
+}
+
+func TestCallEventNoticePrefersCallerName(t *testing.T) {
+	call := &CallEvent{Type: CallMissed, Participants: []CallParticipant{{DisplayName: "Alice", Duration: time.Second}}}
+	if got := call.Notice("Bob"); got != "Missed call from Bob" {
+		t.Fatalf("unexpected notice: %q", got)
+	}
+	if got := (&CallEvent{Type: CallMissed}).Notice(""); got != "Missed call" {
+		t.Fatalf("unexpected notice: %q", got)
+	}
+}
+
+func TestParseCallEventRejectsGarbage(t *testing.T) {
+	for _, raw := range []string{`not xml <`, `<partlist type="weird"></partlist>`} {
+		content, _ := json.Marshal(raw)
+		if ParseCallEvent(content) != nil {
+			t.Fatalf("expected nil for %q", raw)
+		}
+	}
+}
@@ -0,0 +1,365 @@
+package model
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"html"
+	"regexp"
+	"strings"
+)
+
+const (
+	AdaptiveCardContentType  = "application/vnd.microsoft.card.adaptive"
+	HeroCardContentType      = "application/vnd.microsoft.card.hero"
+	ThumbnailCardContentType = "application/vnd.microsoft.card.thumbnail"
+)
+
+// Card is a bot framework card attached to a Teams message.
+type Card struct {
+	ContentType string
+	Content     json.RawMessage
+}
+
+var swiftPattern = regexp.MustCompile(`<Swift\s+b64="([^"]+)"`)
+
+// ParseCards collects the cards of a message. RichText/Media_Card messages carry
+// them base64-encoded in a <Swift> element of the content, while bot messages
+// list them in properties.cards.
+func ParseCards(content json.RawMessage, properties json.RawMessage) []Card {
+	var cards []Card
+	var raw string
+	if err := json.Unmarshal(content, &raw); err == nil {
+		if match := swiftPattern.FindStringSubmatch(raw); match != nil {
+			cards = append(cards, parseSwiftCards(html.UnescapeString(match[1]))...)
+		}
+	}
+	var payload struct {
+		Cards json.RawMessage `json:"cards"`
+	}
+	if len(properties) > 0 && json.Unmarshal(properties, &payload) == nil {
+		cards = append(cards, parseCardList(payload.Cards)...)
+	}
+	return cards
+}
+
+func parseSwiftCards(encoded string) []Card {
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		if decoded, err = base64.RawStdEncoding.DecodeString(encoded); err != nil {
+			return nil
+		}
+	}
+	var swift struct {
+		Attachments json.RawMessage `json:"attachments"`
+	}
+	if err := json.Unmarshal(decoded, &swift); err != nil {
+		return nil
+	}
+	return parseCardList(swift.Attachments)
+}
+
+func parseCardList(raw json.RawMessage) []Card {
+	raw = decodeJSONString(raw)
+	var items []json.RawMessage
+	if err := json.Unmarshal(raw, &items); err != nil {
+		return nil
+	}
+	var cards []Card
+	for _, item := range items {
+		if card, ok := parseCard(item); ok {
+			cards = append(cards, card)
+		}
+	}
+	return cards
+}
+
+func parseCard(raw json.RawMessage) (Card, bool) {
+	var envelope struct {
+		ContentType string          `json:"contentType"`
+		Content     json.RawMessage `json:"content"`
+		Type        string          `json:"type"`
+	}
+	if err := json.Unmarshal(decodeJSONString(raw), &envelope); err != nil {
+		return Card{}, false
+	}
+	if envelope.Type == "AdaptiveCard" {
+		return Card{ContentType: AdaptiveCardContentType, Content: raw}, true
+	}
+	content := decodeJSONString(envelope.Content)
+	switch strings.ToLower(strings.TrimSpace(envelope.ContentType)) {
+	case AdaptiveCardContentType, HeroCardContentType, ThumbnailCardContentType:
+		if len(content) == 0 {
+			return Card{}, false
+		}
+		return Card{ContentType: strings.ToLower(strings.TrimSpace(envelope.ContentType)), Content: content}, true
+	case "":
+		// Some card lists wrap the card without a content type.
+		if len(content) > 0 {
+			return parseCard(content)
+		}
+	}
+	return Card{}, false
+}
+
+// decodeJSONString unwraps JSON values that were encoded into a string.
+func decodeJSONString(raw json.RawMessage) json.RawMessage {
+	var encoded string
+	if err := json.Unmarshal(raw, &encoded); err == nil {
+		return json.RawMessage(strings.TrimSpace(encoded))
+	}
+	return raw
+}
+
+type cardBlock struct {
+	text string
+	html string
+}
+
+type adaptiveAction struct {
+	Type  string          `json:"type"`
+	Title string          `json:"title"`
+	URL   string          `json:"url"`
+	Card  json.RawMessage `json:"card"`
+}
+
+type adaptiveElement struct {
+	Type         string            `json:"type"`
+	ID           string            `json:"id"`
+	Label        string            `json:"label"`
+	Text         string            `json:"text"`
+	Weight       string            `json:"weight"`
+	Size         string            `json:"size"`
+	URL          string            `json:"url"`
+	AltText      string            `json:"altText"`
+	FallbackText string            `json:"fallbackText"`
+	Facts        []cardFact        `json:"facts"`
+	Inlines      []json.RawMessage `json:"inlines"`
+	Body         []adaptiveElement `json:"body"`
+	Items        []adaptiveElement `json:"items"`
+	Columns      []adaptiveElement `json:"columns"`
+	Images       []adaptiveElement `json:"images"`
+	Actions      []adaptiveAction  `json:"actions"`
+	// Choices and IsMultiSelect are only set for Input.ChoiceSet.
+	Choices       []adaptiveChoice `json:"choices"`
+	IsMultiSelect bool             `json:"isMultiSelect"`
+}
+
+type adaptiveChoice struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+type cardFact struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+type heroCard struct {
+	Title    string `json:"title"`
+	Subtitle string `json:"subtitle"`
+	Text     string `json:"text"`
+	Images   []struct {
+		URL string `json:"url"`
+		Alt string `json:"alt"`
+	} `json:"images"`
+	Buttons []struct {
+		Type  string `json:"type"`
+		Title string `json:"title"`
+		Value string `json:"value"`
+	} `json:"buttons"`
+}
+
+// RenderCards renders cards as plain text and Matrix HTML. Each card becomes a
+// blockquote in the HTML; unsupported cards are skipped.
+func RenderCards(cards []Card) (text string, formatted string) {
+	var texts, htmls []string
+	for _, card := range cards {
+		blocks := renderCard(card)
+		if len(blocks) == 0 {
+			continue
+		}
+		cardTexts := make([]string, 0, len(blocks))
+		cardHTMLs := make([]string, 0, len(blocks))
+		for _, block := range blocks {
+			cardTexts = append(cardTexts, block.text)
+			cardHTMLs = append(cardHTMLs, block.html)
+		}
+		texts = append(texts, strings.Join(cardTexts, "\n"))
+		htmls = append(htmls, "<blockquote>"+strings.Join(cardHTMLs, "<br>")+"</blockquote>")
+	}
+	return strings.Join(texts, "\n\n"), strings.Join(htmls, "")
+}
+
+func renderCard(card Card) []cardBlock {
+	switch card.ContentType {
+	case AdaptiveCardContentType:
+		var root adaptiveElement
+		if err := json.Unmarshal(card.Content, &root); err != nil {
+			return nil
+		}
+		var blocks []cardBlock
+		renderAdaptiveElements(&blocks, root.Body)
+		renderAdaptiveActions(&blocks, root.Actions)
+		if len(blocks) == 0 && strings.TrimSpace(root.FallbackText) != "" {
+			blocks = append(blocks, cardTextBlock(root.FallbackText, false))
+		}
+		return blocks
+	case HeroCardContentType, ThumbnailCardContentType:
+		var hero heroCard
+		if err := json.Unmarshal(card.Content, &hero); err != nil {
+			return nil
+		}
+		var blocks []cardBlock
+		if strings.TrimSpace(hero.Title) != "" {
+			blocks = append(blocks, cardTextBlock(hero.Title, true))
+		}
+		for _, value := range []string{hero.Subtitle, hero.Text} {
+			if strings.TrimSpace(value) != "" {
+				blocks = append(blocks, cardTextBlock(value, false))
+			}
+		}
+		for _, image := range hero.Images {
+			if block, ok := cardImageBlock(image.URL, image.Alt); ok {
+				blocks = append(blocks, block)
+			}
+		}
+		actions := make([]adaptiveAction, 0, len(hero.Buttons))
+		for _, button := range hero.Buttons {
+			action := adaptiveAction{Title: button.Title}
+			if strings.EqualFold(button.Type, "openUrl") {
+				action.Type = "Action.OpenUrl"
+				action.URL = button.Value
+			}
+			actions = append(actions, action)
+		}
+		renderAdaptiveActions(&blocks, actions)
+		return blocks
+	default:
+		return nil
+	}
+}
+
+func renderAdaptiveElements(blocks *[]cardBlock, elements []adaptiveElement) {
+	for _, element := range elements {
+		switch element.Type {
+		case "TextBlock":
+			if strings.TrimSpace(element.Text) != "" {
+				bold := strings.EqualFold(element.Weight, "bolder") ||
+					strings.EqualFold(element.Size, "large") || strings.EqualFold(element.Size, "extraLarge")
+				*blocks = append(*blocks, cardTextBlock(element.Text, bold))
+			}
+		case "RichTextBlock":
+			var parts []string
+			for _, inline := range element.Inlines {
+				var plain string
+				if err := json.Unmarshal(inline, &plain); err == nil {
+					parts = append(parts, plain)
+					continue
+				}
+				var run adaptiveElement
+				if err := json.Unmarshal(inline, &run); err == nil {
+					parts = append(parts, run.Text)
+				}
+			}
+			if text := strings.Join(parts, ""); strings.TrimSpace(text) != "" {
+				*blocks = append(*blocks, cardTextBlock(text, false))
+			}
+		case "FactSet":
+			for _, fact := range element.Facts {
+				title := strings.TrimSpace(fact.Title)
+				value := strings.TrimSpace(fact.Value)
+				if title == "" && value == "" {
+					continue
+				}
+				valueText, valueHTML := cardMarkdown(value)
+				*blocks = append(*blocks, cardBlock{
+					text: title + ": " + valueText,
+					html: "<strong>" + html.EscapeString(title) + "</strong>: " + valueHTML,
+				})
+			}
+		case "Image":
+			if block, ok := cardImageBlock(element.URL, element.AltText); ok {
+				*blocks = append(*blocks, block)
+			}
+		case "ImageSet":
+			renderAdaptiveElements(blocks, element.Images)
+		case "Container", "Column":
+			renderAdaptiveElements(blocks, element.Items)
+		case "ColumnSet":
+			renderAdaptiveElements(blocks, element.Columns)
+		case "ActionSet":
+			renderAdaptiveActions(blocks, element.Actions)
+		}
+	}
+}
+
+// renderAdaptiveActions puts all actions on one line. Only links can be
+// followed from Matrix, other actions are listed by title.
+func renderAdaptiveActions(blocks *[]cardBlock, actions []adaptiveAction) {
+	var texts, htmls []string
+	for _, action := range actions {
+		title := strings.TrimSpace(action.Title)
+		target := strings.TrimSpace(action.URL)
+		if action.Type == "Action.OpenUrl" && isCardLink(target) {
+			if title == "" {
+				title = target
+			}
+			texts = append(texts, title+" ("+target+")")
+			htmls = append(htmls, `<a href="`+html.EscapeString(target)+`">`+html.EscapeString(title)+`</a>`)
+			continue
+		}
+		if title != "" {
+			texts = append(texts, "["+title+"]")
+			htmls = append(htmls, "["+html.EscapeString(title)+"]")
+		}
+	}
+	if len(texts) > 0 {
+		*blocks = append(*blocks, cardBlock{text: strings.Join(texts, " · "), html: strings.Join(htmls, " · ")})
+	}
+}
+
+// cardImageBlock links to images, since Matrix HTML can only embed mxc:// URLs.
+func cardImageBlock(url string, alt string) (cardBlock, bool) {
+	url = strings.TrimSpace(url)
+	if !isCardLink(url) {
+		return cardBlock{}, false
+	}
+	label := strings.TrimSpace(alt)
+	if label == "" {
+		label = "Image"
+	}
+	return cardBlock{
+		text: label + ": " + url,
+		html: `<a href="` + html.EscapeString(url) + `">` + html.EscapeString(label) + `</a>`,
+	}, true
+}
+
+func cardTextBlock(value string, bold bool) cardBlock {
+	text, formatted := cardMarkdown(strings.TrimSpace(value))
+	if bold {
+		formatted = "<strong>" + formatted + "</strong>"
+	}
+	return cardBlock{text: text, html: formatted}
+}
+
+func isCardLink(value string) bool {
+	lower := strings.ToLower(value)
+	return strings.HasPrefix(lower, "https://") || strings.HasPrefix(lower, "http://")
+}
+
+var (
+	cardLinkPattern = regexp.MustCompile(`\[([^\]]+)\]\((https?://[^)\s]+)\)`)
+	cardBoldPattern = regexp.MustCompile(`\*\*(.+?)\*\*`)
+)
+
+// cardMarkdown handles the links and bold text of the Markdown subset card text supports.
+func cardMarkdown(value string) (text string, formatted string) {
+	text = cardLinkPattern.ReplaceAllString(value, "$1 ($2)")
+	text = cardBoldPattern.ReplaceAllString(text, "$1")
+
+	formatted = html.EscapeString(value)
+	formatted = cardLinkPattern.ReplaceAllString(formatted, `<a href="$2">$1</a>`)
+	formatted = cardBoldPattern.ReplaceAllString(formatted, "<strong>$1</strong>")
+	formatted = strings.ReplaceAll(strings.ReplaceAll(formatted, "\r\n", "\n"), "\n", "<br>")
+	return text, formatted
+}
@@ -0,0 +1,85 @@
+package model
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+)
+
+const testAdaptiveCard = `{
+	"type": "AdaptiveCard",
+	"version": "1.4",
+	"body": [
+		{"type": "TextBlock", "text": "Team sync", "weight": "bolder"},
+		{"type": "TextBlock", "text": "Agenda in [the doc](https://example.com/doc?a=1&b=2), **be on time**"},
+		{"type": "FactSet", "facts": [{"title": "When", "value": "Monday 10:00"}, {"title": "Where", "value": "Room <1>"}]},
+		{"type": "ColumnSet", "columns": [{"type": "Column", "items": [{"type": "Image", "url": "https://example.com/a.png", "altText": "Logo"}]}]},
+		{"type": "ActionSet", "actions": [
+			{"type": "Action.OpenUrl", "title": "Join", "url": "https://example.com/join"},
+			{"type": "Action.Submit", "title": "Decline"}
+		]}
+	]
+}`
+
+func TestRenderAdaptiveCard(t *testing.T) {
+	text, formatted := RenderCards([]Card{{ContentType: AdaptiveCardContentType, Content: json.RawMessage(testAdaptiveCard)}})
+	wantText := "Team sync\n" +
+		"Agenda in the doc (https://example.com/doc?a=1&b=2), be on time\n" +
+		"When: Monday 10:00\n" +
+		"Where: Room <1>\n" +
+		"Logo: https://example.com/a.png\n" +
+		"Join (https://example.com/join) · [Decline]"
+	if text != wantText {
+		t.Fatalf("unexpected text:\nwant: %q\ngot:  %q", wantText, text)
+	}
+	wantHTML := `<blockquote><strong>Team sync</strong><br>` +
+		`Agenda in <a href="https://example.com/doc?a=1&amp;b=2">the doc</a>, <strong>be on time</strong><br>` +
+		`<strong>When</strong>: Monday 10:00<br>` +
+		`<strong>Where</strong>: Room &lt;1&gt;<br>` +
+		`<a href="https://example.com/a.png">Logo</a><br>` +
+		`<a href="https://example.com/join">Join</a> · [Decline]</blockquote>`
+	if formatted != wantHTML {
+		t.Fatalf("unexpected html:\nwant: %s\ngot:  %s", wantHTML, formatted)
+	}
+}
+
+func TestRenderHeroCard(t *testing.T) {
+	hero := `{"title":"Weather","subtitle":"Today","text":"Sunny","images":[{"url":"https://example.com/sun.png"}],` +
+		`"buttons":[{"type":"openUrl","title":"Forecast","value":"https://example.com/forecast"},{"type":"imBack","title":"Refresh","value":"refresh"}]}`
+	text, formatted := RenderCards([]Card{{ContentType: HeroCardContentType, Content: json.RawMessage(hero)}})
+	wantText := "Weather\nToday\nSunny\nImage: https://example.com/sun.png\nForecast (https://example.com/forecast) · [Refresh]"
+	if text != wantText {
+		t.Fatalf("unexpected text:\nwant: %q\ngot:  %q", wantText, text)
+	}
+	if formatted == "" {
+		t.Fatal("expected html")
+	}
+}
+
+func TestRenderAdaptiveCardFallbackText(t *testing.T) {
+	card := `{"type":"AdaptiveCard","body":[{"type":"Media"}],"fallbackText":"Open in Teams"}`
+	text, _ := RenderCards([]Card{{ContentType: AdaptiveCardContentType, Content: json.RawMessage(card)}})
+	if text != "Open in Teams" {
+		t.Fatalf("unexpected text: %q", text)
+	}
+}
+
+func TestParseCardsFromSwift(t *testing.T) {
+	swift := `{"type":"message/card","attachments":[{"contentType":"application/vnd.microsoft.card.adaptive","content":` + testAdaptiveCard + `}]}`
+	raw := `<URIObject type="SWIFT.1" url_thumbnail="https://example.com/thumb"><Title>Card - access it on</Title>` +
+		`<Swift b64="` + base64.StdEncoding.EncodeToString([]byte(swift)) + `"/></URIObject>`
+	content, _ := json.Marshal(raw)
+	cards := ParseCards(content, nil)
+	if len(cards) != 1 || cards[0].ContentType != AdaptiveCardContentType {
+		t.Fatalf("unexpected cards: %#v", cards)
+	}
+}
+
+func TestParseCardsFromProperties(t *testing.T) {
+	list, _ := json.Marshal(`[{"contentType":"application/vnd.microsoft.card.hero","content":{"title":"Hi"}},{"contentType":"text/plain","content":"x"},` + testAdaptiveCard + `]`)
+	properties := []byte(`{"cards":` + string(list) + `}`)
+	cards := ParseCards([]byte(`"<p>card</p>"`), properties)
+	if len(cards) != 2 || cards[0].ContentType != HeroCardContentType || cards[1].ContentType != AdaptiveCardContentType {
+		t.Fatalf("unexpected cards: %#v", cards)
+	}
+}
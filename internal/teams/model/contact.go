@@ -0,0 +1,88 @@
+package model
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+)
+
+// ContactsMessageType is the messagetype of a shared contact card.
+const ContactsMessageType = "RichText/Contacts"
+
+// Contact is one entry of a shared contact card.
+type Contact struct {
+	// UserID is the Teams user ID for Teams contacts.
+	UserID string
+	Name   string
+	Phone  string
+}
+
+type contactsXML struct {
+	Contacts []struct {
+		Type  string `xml:"t,attr"`
+		ID    string `xml:"s,attr"`
+		Name  string `xml:"f,attr"`
+		Phone string `xml:"p,attr"`
+	} `xml:"c"`
+}
+
+// ParseContacts parses the <contacts> content of a RichText/Contacts message.
+// Each <c> element has the contact's name in f and either a Teams user in s
+// or a phone number in p.
+func ParseContacts(messageType string, content json.RawMessage) []Contact {
+	if !strings.EqualFold(strings.TrimSpace(messageType), ContactsMessageType) {
+		return nil
+	}
+	var raw string
+	if err := json.Unmarshal(content, &raw); err != nil || strings.TrimSpace(raw) == "" {
+		return nil
+	}
+	var parsed contactsXML
+	if err := xml.Unmarshal([]byte(raw), &parsed); err != nil {
+		return nil
+	}
+	var contacts []Contact
+	for _, entry := range parsed.Contacts {
+		contact := Contact{
+			Name:  strings.TrimSpace(entry.Name),
+			Phone: strings.TrimSpace(entry.Phone),
+		}
+		if id := strings.TrimSpace(entry.ID); id != "" {
+			if !strings.Contains(id, ":") || strings.HasPrefix(id, "live:") {
+				// Bare Skype names are consumer accounts.
+				id = "8:" + id
+			}
+			contact.UserID = NormalizeTeamsUserID(id)
+		}
+		if contact.Name == "" {
+			contact.Name = contact.Phone
+		}
+		if contact.Name == "" {
+			contact.Name = contact.UserID
+		}
+		if contact.Name == "" {
+			continue
+		}
+		contacts = append(contacts, contact)
+	}
+	return contacts
+}
+
+// VCard renders the contact as vCard 3.0 text.
+func (c Contact) VCard() string {
+	lines := []string{"BEGIN:VCARD", "VERSION:3.0", "FN:" + escapeVCard(c.Name)}
+	if c.Phone != "" {
+		lines = append(lines, "TEL:"+escapeVCard(c.Phone))
+	}
+	if c.UserID != "" {
+		lines = append(lines, "X-MSTEAMS-ID:"+escapeVCard(c.UserID))
+	}
+	lines = append(lines, "END:VCARD")
+	return strings.Join(lines, "\n")
+}
+
+var vcardEscaper = strings.NewReplacer(`\`, `\\`, ",", `\,`, ";", `\;`, "\n", `\n`)
+
+func escapeVCard(value string) string {
+	return vcardEscaper.Replace(value)
+}
@@ -0,0 +1,31 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseContacts(t *testing.T) {
+	content, _ := json.Marshal(`<contacts><c t="s" s="live:alice" f="Alice Smith"/><c t="p" p="+15551234" f="Bob; Jr."/>` +
+		`<c t="s" s="8:orgid:carol"/><c t="s"/></contacts>`)
+	got := ParseContacts(ContactsMessageType, content)
+	want := []Contact{
+		{UserID: "8:live:alice", Name: "Alice Smith"},
+		{Name: "Bob; Jr.", Phone: "+15551234"},
+		{UserID: "8:orgid:carol", Name: "8:orgid:carol"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("unexpected contacts: %#v", got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("unexpected contact %d:\nwant: %#v\ngot:  %#v", i, want[i], got[i])
+		}
+	}
+	if vcard := got[1].VCard(); vcard != "BEGIN:VCARD\nVERSION:3.0\nFN:Bob\\; Jr.\nTEL:+15551234\nEND:VCARD" {
+		t.Fatalf("unexpected vcard: %q", vcard)
+	}
+	if ParseContacts("RichText/Html", content) != nil {
+		t.Fatal("expected other message types to be ignored")
+	}
+}
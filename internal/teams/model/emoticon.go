@@ -0,0 +1,237 @@
+package model
+
+import (
+	"strconv"
+	"strings"
+
+	"go.mau.fi/util/emojirunes"
+	"go.mau.fi/util/variationselector"
+	"golang.org/x/text/unicode/runenames"
+)
+
+// emoticons is the Teams emoticon catalog, shared by reactions and the
+// <ss type="..."> tags in message bodies. Keys may repeat with different
+// skin tones; several keys may also share an emoji, in which case the last
+// entry wins when mapping emoji to keys.
+var emoticons = []struct {
+	key   string
+	emoji string
+}{
+	{"like", "👍🏻"},
+	{"ok", "👌🏻"},
+	{"fire", "🔥"},
+	{"heartblue", "💙"},
+
+	// Page 1
+	{"smile", "🙂"},
+	{"laugh", "😄"},
+	{"heart", "❤️"},
+	{"kiss", "😘"},
+	{"sad", "☹️"},
+	{"tongueout", "😛"},
+	{"wink", "😉"},
+	{"cry", "😢"},
+	{"inlove", "😍"},
+	{"hug", "🤗"},
+	{"cwl", "😂"},
+	{"lips", "💋"},
+
+	// Page 2
+	{"blush", "😊"},
+	{"surprised", "😮"},
+	{"penguin", "🐧"},
+	{"like", "👍"},
+	{"cool", "😎"},
+	{"rofl", "🤣"},
+	{"cat", "🐱"},
+	{"monkey", "🐵"},
+	{"hi", "👋"},
+	{"snowangel", "❄️"},
+	{"flower", "🌸"},
+	{"giggle", "😁"},
+	{"devil", "😈"},
+	{"party", "🥳"},
+
+	// Page 3
+	{"worry", "😟"},
+	{"champagne", "🍾"},
+	{"sun", "☀️"},
+	{"star", "⭐"},
+	{"polarbear", "🐻‍❄️"},
+	{"eyeroll", "🙄"},
+	{"speechless", "😶"},
+	{"wonder", "🤔"},
+	{"angry", "😠"},
+	{"puke", "🤮"},
+	{"facepalm", "🤦"},
+	{"sweat", "😓"},
+	{"holidayspirit", "🤡"},
+	{"sleepy", "😴"},
+
+	// Page 4
+	{"bow", "🙇"},
+	{"makeup", "💄"},
+	{"cash", "💵"},
+	{"lipssealed", "🤐"},
+	{"shivering", "🥶"},
+	{"cake", "🎂"},
+	{"headbang", "🤕"},
+	{"dance", "💃"},
+	{"wasntme", "😳"},
+	{"hungover", "🤢"},
+	{"yawn", "🥱"},
+	{"gift", "🎁"},
+	{"angel", "😇"},
+	{"xmastree", "🎄"},
+
+	// Page 5
+	{"brokenheart", "💔"},
+	{"think", "🤔"},
+	{"clap", "👏"},
+	{"punch", "👊"},
+	{"envy", "😒"},
+	{"handshake", "🤝"},
+	{"nod", "🙂"},
+	{"nerdy", "🤓"},
+	{"emo", "🖤"},
+	{"muscle", "💪"},
+	{"mmm", "😋"},
+	{"highfive", "🙌"},
+	{"turkey", "🦃"},
+	{"call", "📞"},
+
+	// Page 6
+	{"movember", "🧔"},
+	{"dog", "🐶"},
+	{"coffee", "☕"},
+	{"poke", "👉"},
+	{"swear", "🤬"},
+	{"donttalktome", "😑"},
+	{"fingerscrossed", "🤞"},
+	{"rainbow", "🌈"},
+	{"headphones", "🎧"},
+	{"waiting", "⏳"},
+	{"festiveparty", "🎉"},
+	{"bandit", "🥷"},
+	{"heidy", "🐿️"},
+	{"beer", "🍺"},
+
+	// Page 7
+	{"doh", "🤦‍♂️"},
+	{"bomb", "💣"},
+	{"happy", "😀"},
+	{"ninja", "🥷"},
+}
+
+var emojiToEmoticonKey = func() map[string]string {
+	forward := make(map[string]string, len(emoticons))
+	for _, emoticon := range emoticons {
+		forward[variationselector.FullyQualify(emoticon.emoji)] = emoticon.key
+	}
+	return forward
+}()
+
+var emoticonKeyToEmoji = func() map[string]string {
+	inverse := make(map[string]string, len(emoticons))
+	for _, emoticon := range emoticons {
+		if _, exists := inverse[emoticon.key]; !exists {
+			inverse[emoticon.key] = variationselector.FullyQualify(emoticon.emoji)
+		}
+	}
+	return inverse
+}()
+
+// EmojiToEmoticonKey returns the Teams emoticon key for a Unicode emoji.
+func EmojiToEmoticonKey(emoji string) (string, bool) {
+	if strings.TrimSpace(emoji) == "" {
+		return "", false
+	}
+	key, ok := emojiToEmoticonKey[variationselector.FullyQualify(emoji)]
+	return key, ok
+}
+
+// EmoticonKeyToEmoji returns the Unicode emoji for a Teams emoticon key.
+func EmoticonKeyToEmoji(key string) (string, bool) {
+	key = strings.ToLower(strings.TrimSpace(key))
+	if key == "" {
+		return "", false
+	}
+	emoji, ok := emoticonKeyToEmoji[key]
+	return emoji, ok
+}
+
+// EmojiToReactionKey returns the Teams reaction key for any emoji: the
+// emoticon key when the catalog has one, otherwise the codepoint form Teams
+// uses for its extended emoji picker followed by the Unicode name of the
+// emoji ("1f600_grinningface", "1f44d-1f3fb_thumbsupsign"). Teams clients
+// only render extended reactions sent with the name suffix.
+func EmojiToReactionKey(emoji string) (string, bool) {
+	if key, ok := EmojiToEmoticonKey(emoji); ok {
+		return key, true
+	}
+	emoji = variationselector.Remove(strings.TrimSpace(emoji))
+	if emoji == "" || !emojirunes.IsOnlyEmojis(emoji) {
+		return "", false
+	}
+	parts := make([]string, 0, len(emoji))
+	var name strings.Builder
+	for _, r := range emoji {
+		parts = append(parts, strconv.FormatInt(int64(r), 16))
+		if isEmojiModifierRune(r) {
+			continue
+		}
+		for _, c := range strings.ToLower(runenames.Name(r)) {
+			if (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') {
+				name.WriteRune(c)
+			}
+		}
+	}
+	key := strings.Join(parts, "-")
+	if name.Len() > 0 {
+		key += "_" + name.String()
+	}
+	return key, true
+}
+
+// isEmojiModifierRune reports whether r only modifies the emoji before it and
+// so is left out of the reaction key name: skin tones, joiners and tags.
+func isEmojiModifierRune(r rune) bool {
+	return (r >= 0x1f3fb && r <= 0x1f3ff) || r == 0x200d || (r >= 0xe0020 && r <= 0xe007f)
+}
+
+// ReactionEmojiID returns the canonical form of a Teams reaction key, used
+// as the bridge's emoji ID in both directions: emoticon keys as-is, and
+// codepoint keys lower-cased without the name suffix ("1f600").
+func ReactionEmojiID(key string) string {
+	key = strings.ToLower(strings.TrimSpace(key))
+	if _, ok := emoticonKeyToEmoji[key]; ok {
+		return key
+	}
+	codepoints, _, _ := strings.Cut(key, "_")
+	return codepoints
+}
+
+// ReactionKeyToEmoji is the inverse of EmojiToReactionKey. Codepoint keys are
+// accepted with or without the name suffix.
+func ReactionKeyToEmoji(key string) (string, bool) {
+	if emoji, ok := EmoticonKeyToEmoji(key); ok {
+		return emoji, true
+	}
+	codepoints, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(key)), "_")
+	if codepoints == "" {
+		return "", false
+	}
+	var b strings.Builder
+	for _, part := range strings.Split(codepoints, "-") {
+		r, err := strconv.ParseUint(part, 16, 32)
+		if err != nil {
+			return "", false
+		}
+		b.WriteRune(rune(r))
+	}
+	emoji := b.String()
+	if !emojirunes.IsOnlyEmojis(emoji) {
+		return "", false
+	}
+	return variationselector.FullyQualify(emoji), true
+}
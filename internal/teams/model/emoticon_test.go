@@ -0,0 +1,101 @@
+package model
+
+import "testing"
+
+func TestEmoticonCatalogRoundTrip(t *testing.T) {
+	if key, ok := EmojiToEmoticonKey("❤"); !ok || key != "heart" {
+		t.Fatalf("unexpected key for unqualified heart: %q %v", key, ok)
+	}
+	if key, ok := EmojiToEmoticonKey("🤔"); !ok || key != "think" {
+		t.Fatalf("expected later entry to win, got %q", key)
+	}
+	if emoji, ok := EmoticonKeyToEmoji("Smile"); !ok || emoji != "🙂" {
+		t.Fatalf("unexpected emoji for smile: %q %v", emoji, ok)
+	}
+	if _, ok := EmoticonKeyToEmoji("nosuchkey"); ok {
+		t.Fatalf("expected unknown key to fail")
+	}
+}
+
+func TestNormalizeMessageBodyReplacesEmoticons(t *testing.T) {
+	content := NormalizeMessageBody(`<p>hi <emoji id="smile" alt="🙂" title="Smile"><img src="https://statics.teams.cdn.live.net/smile.png"></emoji> <b>ok</b></p>`)
+	if content.Body != "hi 🙂 ok" {
+		t.Fatalf("unexpected plaintext body: %q", content.Body)
+	}
+	if content.FormattedBody != "<p>hi 🙂 <b>ok</b></p>" {
+		t.Fatalf("unexpected formatted body: %q", content.FormattedBody)
+	}
+
+	legacy := NormalizeMessageBody(`nice <ss type="laugh">(laugh)</ss> <ss type="unknownthing">(unknownthing)</ss>`)
+	if legacy.Body != "nice 😄 (unknownthing)" || legacy.FormattedBody != "" {
+		t.Fatalf("unexpected legacy content: %#v", legacy)
+	}
+}
+
+func TestReactionKeyCodepointScheme(t *testing.T) {
+	tests := []struct {
+		emoji string
+		key   string
+	}{
+		{emoji: "🙂", key: "nod"},
+		{emoji: "🫠", key: "1fae0_meltingface"},
+		{emoji: "🦖", key: "1f996_trex"},
+		{emoji: "👍🏽", key: "1f44d-1f3fd_thumbsupsign"},
+		{emoji: "🏳️‍🌈", key: "1f3f3-200d-1f308_wavingwhiteflagrainbow"},
+	}
+	for _, tc := range tests {
+		key, ok := EmojiToReactionKey(tc.emoji)
+		if !ok || key != tc.key {
+			t.Fatalf("unexpected key for %q: %q %v", tc.emoji, key, ok)
+		}
+	}
+	if _, ok := EmojiToReactionKey("abc"); ok {
+		t.Fatalf("expected text to be rejected")
+	}
+
+	for key, want := range map[string]string{
+		"1f600_grinningface": "😀",
+		"1F996":              "🦖",
+		"1f3f3-200d-1f308":   "🏳️‍🌈",
+		"smile":              "🙂",
+	} {
+		emoji, ok := ReactionKeyToEmoji(key)
+		if !ok || emoji != want {
+			t.Fatalf("unexpected emoji for %q: %q %v", key, emoji, ok)
+		}
+	}
+	for _, key := range []string{"", "cafe", "zz_name", "41"} {
+		if emoji, ok := ReactionKeyToEmoji(key); ok {
+			t.Fatalf("expected %q to be rejected, got %q", key, emoji)
+		}
+	}
+}
+
+func TestReactionEmojiID(t *testing.T) {
+	for key, want := range map[string]string{
+		"1f600_grinningface": "1f600",
+		"1F600_GrinningFace": "1f600",
+		"1f600":              "1f600",
+		"1f44d-1f3fd_thumbs": "1f44d-1f3fd",
+		"Like":               "like",
+		"":                   "",
+	} {
+		if got := ReactionEmojiID(key); got != want {
+			t.Fatalf("unexpected emoji id for %q: %q", key, got)
+		}
+	}
+	for _, emoji := range []string{"😀", "🫠", "🦖", "👍🏽"} {
+		key, ok := EmojiToReactionKey(emoji)
+		if !ok {
+			t.Fatalf("expected key for %q", emoji)
+		}
+		back, ok := ReactionKeyToEmoji(key)
+		if !ok {
+			t.Fatalf("expected emoji for %q", key)
+		}
+		again, _ := EmojiToReactionKey(back)
+		if ReactionEmojiID(again) != ReactionEmojiID(key) {
+			t.Fatalf("emoji id for %q changed on round trip: %q -> %q", emoji, key, again)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package model
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	nethtml "golang.org/x/net/html"
+)
+
+const ForwardItemType = "http://schema.skype.com/Forward"
+
+// MessageForward describes the original of a forwarded Teams message.
+type MessageForward struct {
+	MessageID  string
+	SenderID   string
+	SenderName string
+	Timestamp  time.Time
+}
+
+func findForwardBlock(node *nethtml.Node) *nethtml.Node {
+	if node.Type == nethtml.ElementNode &&
+		strings.EqualFold(strings.TrimSpace(nodeAttr(node, "itemtype")), ForwardItemType) {
+		return node
+	}
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if found := findForwardBlock(child); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// extractForward reads the original sender and time out of a forward block and
+// replaces the block with the forwarded content, so only the content is rendered.
+func extractForward(block *nethtml.Node) *MessageForward {
+	forward := &MessageForward{MessageID: strings.TrimSpace(nodeAttr(block, "itemid"))}
+	var walk func(node *nethtml.Node)
+	walk = func(node *nethtml.Node) {
+		for child := node.FirstChild; child != nil; {
+			next := child.NextSibling
+			if child.Type == nethtml.ElementNode {
+				switch strings.ToLower(strings.TrimSpace(nodeAttr(child, "itemprop"))) {
+				case "mri":
+					if forward.SenderID == "" {
+						forward.SenderID = NormalizeTeamsUserID(nodeAttr(child, "itemid"))
+						forward.SenderName = normalizePlainText(nodeText(child))
+					}
+					node.RemoveChild(child)
+				case "time":
+					if ms, err := strconv.ParseInt(strings.TrimSpace(nodeAttr(child, "itemid")), 10, 64); err == nil && ms > 0 && forward.Timestamp.IsZero() {
+						forward.Timestamp = time.UnixMilli(ms).UTC()
+					}
+					node.RemoveChild(child)
+				default:
+					walk(child)
+				}
+			}
+			child = next
+		}
+	}
+	walk(block)
+
+	parent := block.Parent
+	for child := block.FirstChild; child != nil; {
+		next := child.NextSibling
+		block.RemoveChild(child)
+		parent.InsertBefore(child, block)
+		child = next
+	}
+	parent.RemoveChild(block)
+	return forward
+}
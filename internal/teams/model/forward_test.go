@@ -0,0 +1,31 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNormalizeMessageBodyForward(t *testing.T) {
+	raw := `<p>look at this</p><blockquote itemscope="" itemtype="http://schema.skype.com/Forward" itemid="1700000000000">` +
+		`<strong itemprop="mri" itemid="8:live:alice">Alice</strong><span itemprop="time" itemid="1700000000000"></span>` +
+		`<p>original <b>text</b></p></blockquote>`
+	content := NormalizeMessageBody(raw)
+	if content.ForwardedFrom == nil {
+		t.Fatal("expected forward")
+	}
+	want := MessageForward{
+		MessageID:  "1700000000000",
+		SenderID:   "8:live:alice",
+		SenderName: "Alice",
+		Timestamp:  time.UnixMilli(1700000000000).UTC(),
+	}
+	if *content.ForwardedFrom != want {
+		t.Fatalf("unexpected forward:\nwant: %#v\ngot:  %#v", want, *content.ForwardedFrom)
+	}
+	if content.Body != "look at this\noriginal text" {
+		t.Fatalf("unexpected body: %q", content.Body)
+	}
+	if content.FormattedBody != "<p>look at this</p><p>original <b>text</b></p>" {
+		t.Fatalf("unexpected formatted body: %q", content.FormattedBody)
+	}
+}
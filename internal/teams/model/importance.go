@@ -0,0 +1,55 @@
+package model
+
+import (
+	"encoding/json"
+	"html"
+	"strings"
+)
+
+// MessageImportance is the importance flag a sender set on a Teams message.
+type MessageImportance string
+
+const (
+	ImportanceNormal MessageImportance = ""
+	ImportanceHigh   MessageImportance = "high"
+	ImportanceUrgent MessageImportance = "urgent"
+)
+
+// ParseImportance normalizes an importance value. Teams clients label "high"
+// as Important, so that spelling is accepted too.
+func ParseImportance(value string) MessageImportance {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "high", "important":
+		return ImportanceHigh
+	case "urgent":
+		return ImportanceUrgent
+	default:
+		return ImportanceNormal
+	}
+}
+
+// ExtractImportance reads properties.importance.
+func ExtractImportance(properties json.RawMessage) MessageImportance {
+	return ParseImportance(extractPropertyString(properties, "importance"))
+}
+
+// ExtractSubject reads the subject line of a message from properties.subject.
+func ExtractSubject(properties json.RawMessage) string {
+	subject := extractPropertyString(properties, "subject")
+	return normalizePlainText(strings.ReplaceAll(html.UnescapeString(subject), "\n", " "))
+}
+
+func extractPropertyString(properties json.RawMessage, key string) string {
+	if len(properties) == 0 {
+		return ""
+	}
+	var payload map[string]json.RawMessage
+	if err := json.Unmarshal(properties, &payload); err != nil {
+		return ""
+	}
+	var value string
+	if err := json.Unmarshal(payload[key], &value); err != nil {
+		return ""
+	}
+	return strings.TrimSpace(value)
+}
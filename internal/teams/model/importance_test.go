@@ -0,0 +1,48 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestExtractImportance(t *testing.T) {
+	tests := []struct {
+		name       string
+		properties string
+		want       MessageImportance
+	}{
+		{name: "Missing", properties: `{}`, want: ImportanceNormal},
+		{name: "Normal", properties: `{"importance":"normal"}`, want: ImportanceNormal},
+		{name: "High", properties: `{"importance":"high"}`, want: ImportanceHigh},
+		{name: "Urgent", properties: `{"importance":"Urgent"}`, want: ImportanceUrgent},
+		{name: "NotAString", properties: `{"importance":2}`, want: ImportanceNormal},
+		{name: "InvalidJSON", properties: `{`, want: ImportanceNormal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExtractImportance(json.RawMessage(tt.properties)); got != tt.want {
+				t.Fatalf("unexpected importance: want %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestExtractSubject(t *testing.T) {
+	tests := []struct {
+		name       string
+		properties string
+		want       string
+	}{
+		{name: "Missing", properties: `{}`, want: ""},
+		{name: "Subject", properties: `{"subject":"  Quarterly &amp; plan "}`, want: "Quarterly & plan"},
+		{name: "TitleIgnored", properties: `{"title":"Release notes"}`, want: ""},
+		{name: "Newlines", properties: `{"subject":"two\nlines"}`, want: "two lines"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExtractSubject(json.RawMessage(tt.properties)); got != tt.want {
+				t.Fatalf("unexpected subject: want %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package model
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	nethtml "golang.org/x/net/html"
+)
+
+const amsImageItemType = "http://schema.skype.com/AMSImage"
+
+// InlineImage is a picture pasted into a Teams message body. It lives in AMS
+// (the Skype media store) rather than OneDrive, and downloading it requires the
+// skypetoken.
+type InlineImage struct {
+	URL      string
+	ObjectID string
+	Alt      string
+	Width    int
+	Height   int
+}
+
+// ParseInlineImagesFromHTML collects <img> tags pointing at AMS objects.
+func ParseInlineImagesFromHTML(raw string) []InlineImage {
+	if !looksLikeHTML(raw) || !strings.Contains(raw, "asm.skype.com") {
+		return nil
+	}
+	doc, err := nethtml.Parse(strings.NewReader("<div>" + raw + "</div>"))
+	if err != nil {
+		return nil
+	}
+	var images []InlineImage
+	seen := make(map[string]struct{})
+	var walk func(node *nethtml.Node)
+	walk = func(node *nethtml.Node) {
+		if node.Type == nethtml.ElementNode && strings.EqualFold(node.Data, "img") && !hasGiphyItemType(node) {
+			src := strings.TrimSpace(getAttr(node, "src"))
+			if objectID := AMSObjectID(src); objectID != "" {
+				if _, ok := seen[objectID]; !ok {
+					seen[objectID] = struct{}{}
+					width, _ := strconv.Atoi(strings.TrimSpace(getAttr(node, "width")))
+					height, _ := strconv.Atoi(strings.TrimSpace(getAttr(node, "height")))
+					images = append(images, InlineImage{
+						URL:      src,
+						ObjectID: objectID,
+						Alt:      strings.TrimSpace(getAttr(node, "alt")),
+						Width:    width,
+						Height:   height,
+					})
+				}
+			}
+		}
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(doc)
+	return images
+}
+
+// AMSObjectID returns the object ID of an https://*.asm.skype.com/v1/objects/{id}/...
+// URL, or "" if the URL isn't an AMS object.
+func AMSObjectID(value string) string {
+	parsed, err := url.Parse(strings.TrimSpace(value))
+	if err != nil || !strings.EqualFold(parsed.Scheme, "https") || !IsAMSHost(parsed.Hostname()) {
+		return ""
+	}
+	rest, ok := strings.CutPrefix(parsed.EscapedPath(), "/v1/objects/")
+	if !ok {
+		return ""
+	}
+	objectID, _, _ := strings.Cut(rest, "/")
+	objectID, err = url.PathUnescape(objectID)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(objectID)
+}
+
+// IsAMSHost reports whether host belongs to AMS, so the skypetoken is never
+// sent anywhere else.
+func IsAMSHost(host string) bool {
+	host = strings.ToLower(strings.TrimSpace(host))
+	return host == "asm.skype.com" || strings.HasSuffix(host, ".asm.skype.com")
+}
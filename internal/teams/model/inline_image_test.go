@@ -0,0 +1,45 @@
+package model
+
+import "testing"
+
+func TestParseInlineImagesFromHTML(t *testing.T) {
+	raw := `<p>look</p><p><img itemscope="" itemtype="http://schema.skype.com/AMSImage" width="250" height="140" alt="image" ` +
+		`src="https://eu-api.asm.skype.com/v1/objects/0-weu-d1-abc/views/imgo" id="x_0-weu-d1-abc"></p>` +
+		`<img src="https://eu-api.asm.skype.com/v1/objects/0-weu-d1-abc/views/imgpsh_fullsize_anim">` +
+		`<img src="https://evil.example.com/v1/objects/1/views/imgo">` +
+		`<img src="https://media4.giphy.com/media/test/giphy.gif" itemtype="http://schema.skype.com/Giphy">`
+	images := ParseInlineImagesFromHTML(raw)
+	if len(images) != 1 {
+		t.Fatalf("expected one image, got %#v", images)
+	}
+	got := images[0]
+	if got.ObjectID != "0-weu-d1-abc" || got.Width != 250 || got.Height != 140 || got.Alt != "image" {
+		t.Fatalf("unexpected image: %#v", got)
+	}
+	if got.URL != "https://eu-api.asm.skype.com/v1/objects/0-weu-d1-abc/views/imgo" {
+		t.Fatalf("unexpected image url: %q", got.URL)
+	}
+}
+
+func TestAMSObjectID(t *testing.T) {
+	cases := map[string]string{
+		"https://eu-api.asm.skype.com/v1/objects/0-weu-d1-abc/views/imgo": "0-weu-d1-abc",
+		"https://asm.skype.com/v1/objects/id%2F1/views/imgo":              "id/1",
+		"http://eu-api.asm.skype.com/v1/objects/0-weu-d1-abc/views/imgo":  "",
+		"https://asm.skype.com.evil.com/v1/objects/abc/views/imgo":        "",
+		"https://eu-api.asm.skype.com/v2/objects/abc":                     "",
+		"": "",
+	}
+	for input, want := range cases {
+		if got := AMSObjectID(input); got != want {
+			t.Errorf("AMSObjectID(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestExtractContentIncludesInlineImages(t *testing.T) {
+	content := ExtractContent([]byte(`"<p><img src=\"https://us-api.asm.skype.com/v1/objects/0-eus-d2-1/views/imgo\" itemtype=\"http://schema.skype.com/AMSImage\"></p>"`))
+	if len(content.InlineImages) != 1 || content.InlineImages[0].ObjectID != "0-eus-d2-1" {
+		t.Fatalf("unexpected inline images: %#v", content.InlineImages)
+	}
+}
@@ -0,0 +1,72 @@
+package model
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// LinkPreview is the URL preview Teams generated for a link in a message.
+type LinkPreview struct {
+	URL         string
+	Title       string
+	Description string
+	// ImageURL is the preview thumbnail, usually proxied through AMS.
+	ImageURL    string
+	ImageWidth  int
+	ImageHeight int
+}
+
+// ExtractLinkPreviews parses properties.links. Links without preview metadata,
+// or whose preview the sender removed, are skipped.
+func ExtractLinkPreviews(properties json.RawMessage) []LinkPreview {
+	if len(properties) == 0 {
+		return nil
+	}
+	var payload struct {
+		Links json.RawMessage `json:"links"`
+	}
+	if err := json.Unmarshal(properties, &payload); err != nil || len(payload.Links) == 0 {
+		return nil
+	}
+	var links []struct {
+		URL            string `json:"url"`
+		PreviewEnabled *bool  `json:"previewenabled"`
+		Preview        *struct {
+			Title       string `json:"title"`
+			Description string `json:"description"`
+			PreviewURL  string `json:"previewurl"`
+			PreviewMeta []struct {
+				Type   string `json:"type"`
+				Width  int    `json:"width"`
+				Height int    `json:"height"`
+			} `json:"previewmeta"`
+		} `json:"preview"`
+	}
+	if err := json.Unmarshal(decodeJSONString(payload.Links), &links); err != nil {
+		return nil
+	}
+	var previews []LinkPreview
+	for _, link := range links {
+		url := strings.TrimSpace(link.URL)
+		if url == "" || link.Preview == nil || (link.PreviewEnabled != nil && !*link.PreviewEnabled) {
+			continue
+		}
+		preview := LinkPreview{
+			URL:         url,
+			Title:       strings.TrimSpace(link.Preview.Title),
+			Description: strings.TrimSpace(link.Preview.Description),
+			ImageURL:    strings.TrimSpace(link.Preview.PreviewURL),
+		}
+		if preview.Title == "" && preview.Description == "" {
+			continue
+		}
+		for _, meta := range link.Preview.PreviewMeta {
+			if strings.EqualFold(meta.Type, "image") {
+				preview.ImageWidth, preview.ImageHeight = meta.Width, meta.Height
+				break
+			}
+		}
+		previews = append(previews, preview)
+	}
+	return previews
+}
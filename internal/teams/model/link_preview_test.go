@@ -0,0 +1,41 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestExtractLinkPreviews(t *testing.T) {
+	links, _ := json.Marshal(`[` +
+		`{"@type":"http://schema.skype.com/HyperLink","itemid":0,"url":"https://example.com/a","previewenabled":true,` +
+		`"preview":{"title":"Example A","description":"About A","previewurl":"https://urlp.asm.skype.com/v1/url/content?url=https%3a%2f%2fexample.com%2fa.png",` +
+		`"previewmeta":[{"type":"Image","width":640,"height":360}]}},` +
+		`{"url":"https://example.com/b","previewenabled":false,"preview":{"title":"Removed"}},` +
+		`{"url":"https://example.com/c"}` +
+		`]`)
+	properties := []byte(`{"links":` + string(links) + `}`)
+
+	previews := ExtractLinkPreviews(properties)
+	if len(previews) != 1 {
+		t.Fatalf("expected one preview, got %#v", previews)
+	}
+	want := LinkPreview{
+		URL:         "https://example.com/a",
+		Title:       "Example A",
+		Description: "About A",
+		ImageURL:    "https://urlp.asm.skype.com/v1/url/content?url=https%3a%2f%2fexample.com%2fa.png",
+		ImageWidth:  640,
+		ImageHeight: 360,
+	}
+	if previews[0] != want {
+		t.Fatalf("unexpected preview:\nwant: %#v\ngot:  %#v", want, previews[0])
+	}
+}
+
+func TestExtractLinkPreviewsMissing(t *testing.T) {
+	for _, properties := range []string{``, `{}`, `{"links":"[]"}`, `{"links":"not json"}`} {
+		if got := ExtractLinkPreviews([]byte(properties)); got != nil {
+			t.Fatalf("expected no previews for %q, got %#v", properties, got)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package model
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// LocationMessageType is the messagetype of a shared location.
+const LocationMessageType = "RichText/Location"
+
+// Location is a shared location. Teams links it to Bing Maps.
+type Location struct {
+	Latitude  float64
+	Longitude float64
+	// Name is the point of interest, if the sender picked one.
+	Name    string
+	Address string
+}
+
+type locationXML struct {
+	Latitude        string `xml:"latitude,attr"`
+	Longitude       string `xml:"longitude,attr"`
+	PointOfInterest string `xml:"pointOfInterest,attr"`
+	Address         string `xml:"address,attr"`
+	ShortAddress    string `xml:"shortAddress,attr"`
+}
+
+// ParseLocation parses the <location> content of a RichText/Location message.
+// It returns nil for other message types or when the coordinates are invalid.
+func ParseLocation(messageType string, content json.RawMessage) *Location {
+	if !strings.EqualFold(strings.TrimSpace(messageType), LocationMessageType) {
+		return nil
+	}
+	var raw string
+	if err := json.Unmarshal(content, &raw); err != nil || strings.TrimSpace(raw) == "" {
+		return nil
+	}
+	var parsed locationXML
+	if err := xml.Unmarshal([]byte(raw), &parsed); err != nil {
+		return nil
+	}
+	latitude, longitude, ok := parseLocationCoordinates(parsed.Latitude, parsed.Longitude)
+	if !ok {
+		return nil
+	}
+	location := &Location{
+		Latitude:  latitude,
+		Longitude: longitude,
+		Name:      strings.TrimSpace(parsed.PointOfInterest),
+		Address:   strings.TrimSpace(parsed.Address),
+	}
+	if location.Address == "" {
+		location.Address = strings.TrimSpace(parsed.ShortAddress)
+	}
+	return location
+}
+
+// parseLocationCoordinates accepts degrees as well as the integer microdegrees
+// Teams and Skype clients send. Integers are only read as microdegrees when
+// one of them is out of range as degrees, so latitude="48" stays 48°.
+func parseLocationCoordinates(rawLatitude, rawLongitude string) (latitude float64, longitude float64, ok bool) {
+	latitude, latitudeIsInt, ok := parseLocationNumber(rawLatitude)
+	if !ok {
+		return 0, 0, false
+	}
+	longitude, longitudeIsInt, ok := parseLocationNumber(rawLongitude)
+	if !ok {
+		return 0, 0, false
+	}
+	if latitudeIsInt && longitudeIsInt && (math.Abs(latitude) > 90 || math.Abs(longitude) > 180) {
+		latitude /= 1e6
+		longitude /= 1e6
+	}
+	if math.Abs(latitude) > 90 || math.Abs(longitude) > 180 {
+		return 0, 0, false
+	}
+	return latitude, longitude, true
+}
+
+func parseLocationNumber(value string) (number float64, isInt bool, ok bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false, false
+	}
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(number) || math.IsInf(number, 0) {
+		return 0, false, false
+	}
+	_, err = strconv.ParseInt(value, 10, 64)
+	return number, err == nil, true
+}
+
+// MicrodegreeString formats a coordinate as the integer microdegrees used in
+// the latitude and longitude attributes of Teams location messages.
+func MicrodegreeString(value float64) string {
+	return strconv.FormatInt(int64(math.Round(value*1e6)), 10)
+}
+
+// GeoURI formats the location as an RFC 5870 geo: URI.
+func (l Location) GeoURI() string {
+	return "geo:" + formatCoordinate(l.Latitude) + "," + formatCoordinate(l.Longitude)
+}
+
+// Description is the point of interest and address joined for display.
+func (l Location) Description() string {
+	var parts []string
+	for _, part := range []string{l.Name, l.Address} {
+		if part = strings.TrimSpace(part); part != "" && !containsString(parts, part) {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ParseGeoURI reads the coordinates of a geo: URI, ignoring altitude and parameters.
+func ParseGeoURI(uri string) (latitude float64, longitude float64, ok bool) {
+	uri = strings.TrimSpace(uri)
+	if len(uri) < 4 || !strings.EqualFold(uri[:4], "geo:") {
+		return 0, 0, false
+	}
+	coordinates, _, _ := strings.Cut(uri[4:], ";")
+	parts := strings.Split(coordinates, ",")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, 0, false
+	}
+	latitude, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	if err != nil || math.IsNaN(latitude) || math.Abs(latitude) > 90 {
+		return 0, 0, false
+	}
+	longitude, err = strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if err != nil || math.IsNaN(longitude) || math.Abs(longitude) > 180 {
+		return 0, 0, false
+	}
+	return latitude, longitude, true
+}
+
+func formatCoordinate(value float64) string {
+	return strconv.FormatFloat(value, 'f', 6, 64)
+}
+
+func containsString(values []string, value string) bool {
+	for _, existing := range values {
+		if existing == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseLocation(t *testing.T) {
+	tests := []struct {
+		name        string
+		messageType string
+		content     string
+		want        *Location
+	}{
+		{
+			name:        "Microdegrees",
+			messageType: LocationMessageType,
+			content: `<location isUserLocation="0" latitude="47640061" longitude="-122129797" pointOfInterest="Building 92" ` +
+				`address="15010 NE 36th St, Redmond"><a href="https://www.bing.com/maps">Building 92</a></location>`,
+			want: &Location{Latitude: 47.640061, Longitude: -122.129797, Name: "Building 92", Address: "15010 NE 36th St, Redmond"},
+		},
+		{
+			name:        "DegreesWithShortAddress",
+			messageType: LocationMessageType,
+			content:     `<location latitude="52.2297" longitude="21.0122" shortAddress="Warsaw"></location>`,
+			want:        &Location{Latitude: 52.2297, Longitude: 21.0122, Address: "Warsaw"},
+		},
+		{
+			name:        "IntegerDegrees",
+			messageType: LocationMessageType,
+			content:     `<location latitude="48" longitude="11"></location>`,
+			want:        &Location{Latitude: 48, Longitude: 11},
+		},
+		{
+			name:        "MicrodegreesNearPrimeMeridian",
+			messageType: LocationMessageType,
+			content:     `<location latitude="51503396" longitude="-127"></location>`,
+			want:        &Location{Latitude: 51.503396, Longitude: -0.000127},
+		},
+		{name: "OutOfRange", messageType: LocationMessageType, content: `<location latitude="91.5" longitude="0.5"></location>`},
+		{name: "MicrodegreesOutOfRange", messageType: LocationMessageType, content: `<location latitude="91000000" longitude="0"></location>`},
+		{name: "MissingCoordinates", messageType: LocationMessageType, content: `<location address="Nowhere"></location>`},
+		{name: "OtherType", messageType: "RichText/Html", content: `<location latitude="1.5" longitude="1.5"></location>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, _ := json.Marshal(tt.content)
+			got := ParseLocation(tt.messageType, content)
+			if tt.want == nil {
+				if got != nil {
+					t.Fatalf("expected nil, got %#v", got)
+				}
+				return
+			}
+			if got == nil || *got != *tt.want {
+				t.Fatalf("unexpected location:\nwant: %#v\ngot:  %#v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestLocationGeoURIRoundTrip(t *testing.T) {
+	location := Location{Latitude: 47.640061, Longitude: -122.129797, Name: "Building 92", Address: "Building 92"}
+	if uri := location.GeoURI(); uri != "geo:47.640061,-122.129797" {
+		t.Fatalf("unexpected geo uri: %q", uri)
+	}
+	if description := location.Description(); description != "Building 92" {
+		t.Fatalf("unexpected description: %q", description)
+	}
+	latitude, longitude, ok := ParseGeoURI("geo:47.640061,-122.129797,30;u=35")
+	if !ok || latitude != 47.640061 || longitude != -122.129797 {
+		t.Fatalf("unexpected parsed geo uri: %v %v %v", latitude, longitude, ok)
+	}
+	for _, invalid := range []string{"", "geo:", "geo:1", "geo:100,0", "https://example.com", "geo:a,b"} {
+		if _, _, ok = ParseGeoURI(invalid); ok {
+			t.Fatalf("expected %q to be rejected", invalid)
+		}
+	}
+}
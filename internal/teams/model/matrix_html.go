@@ -0,0 +1,167 @@
+package model
+
+import (
+	"html"
+	"strconv"
+	"strings"
+
+	nethtml "golang.org/x/net/html"
+)
+
+// teamsFormattedTags is the allowlist for Matrix HTML sent to Teams. Matrix
+// synonyms Teams doesn't render are renamed; everything else is unwrapped.
+var teamsFormattedTags = map[string]string{
+	"a":          "a",
+	"b":          "b",
+	"blockquote": "blockquote",
+	"br":         "br",
+	"code":       "code",
+	"del":        "s",
+	"em":         "em",
+	"h1":         "h1",
+	"h2":         "h2",
+	"h3":         "h3",
+	"h4":         "h4",
+	"h5":         "h5",
+	"h6":         "h6",
+	"hr":         "hr",
+	"i":          "i",
+	"li":         "li",
+	"ol":         "ol",
+	"p":          "p",
+	"pre":        "pre",
+	"s":          "s",
+	"strike":     "s",
+	"strong":     "strong",
+	"sub":        "sub",
+	"sup":        "sup",
+	"u":          "u",
+	"ul":         "ul",
+}
+
+var teamsVoidTags = map[string]bool{
+	"br": true,
+	"hr": true,
+}
+
+// MentionResolver maps a link target (usually a matrix.to pill) to the MRI of
+// the Teams user it mentions, or "" if the link isn't a mention.
+type MentionResolver func(href string) string
+
+// ConvertMatrixHTML is the reverse of NormalizeMessageBody: it sanitizes a
+// Matrix formatted_body into Teams RichText/Html. Pills that resolve to Teams
+// users become mention spans and are returned with their item IDs. Reply
+// fallbacks are dropped, since replies are sent as Teams quotes. An empty
+// result means nothing renderable was left.
+func ConvertMatrixHTML(formatted string, resolve MentionResolver) (string, []MessageMention) {
+	doc, err := nethtml.Parse(strings.NewReader("<div>" + formatted + "</div>"))
+	if err != nil {
+		return "", nil
+	}
+	wrapper := findWrapperDiv(doc)
+	if wrapper == nil {
+		return "", nil
+	}
+	conv := &matrixHTMLConverter{resolve: resolve}
+
+	// Teams expects top-level text inside paragraphs, so runs of inline
+	// nodes between blocks are wrapped in <p>.
+	var out, inline strings.Builder
+	flushInline := func() {
+		if strings.TrimSpace(inline.String()) != "" {
+			out.WriteString("<p>")
+			out.WriteString(strings.TrimSpace(inline.String()))
+			out.WriteString("</p>")
+		}
+		inline.Reset()
+	}
+	for child := wrapper.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == nethtml.ElementNode && blockTags[strings.ToLower(child.Data)] {
+			flushInline()
+			conv.render(&out, child, false)
+		} else {
+			conv.render(&inline, child, false)
+		}
+	}
+	flushInline()
+	return out.String(), conv.mentions
+}
+
+type matrixHTMLConverter struct {
+	resolve  MentionResolver
+	mentions []MessageMention
+}
+
+func (conv *matrixHTMLConverter) render(b *strings.Builder, node *nethtml.Node, inPre bool) {
+	switch node.Type {
+	case nethtml.TextNode:
+		text := node.Data
+		if !inPre {
+			// Source newlines are insignificant in HTML; Teams would show them.
+			text = strings.ReplaceAll(text, "\n", " ")
+		}
+		b.WriteString(html.EscapeString(text))
+		return
+	case nethtml.ElementNode:
+	default:
+		conv.renderChildren(b, node, inPre)
+		return
+	}
+
+	tag := strings.ToLower(node.Data)
+	if isUnsafeTag(tag) || tag == "mx-reply" {
+		return
+	}
+	if tag == "img" {
+		// mxc:// URLs mean nothing to Teams, so keep the description at least.
+		if alt := strings.TrimSpace(nodeAttr(node, "alt")); alt != "" {
+			b.WriteString(html.EscapeString(alt))
+		}
+		return
+	}
+	if tag == "a" {
+		conv.renderLink(b, node, inPre)
+		return
+	}
+	teamsTag, ok := teamsFormattedTags[tag]
+	if !ok {
+		conv.renderChildren(b, node, inPre)
+		return
+	}
+	b.WriteString("<" + teamsTag + ">")
+	if teamsVoidTags[teamsTag] {
+		return
+	}
+	conv.renderChildren(b, node, inPre || teamsTag == "pre")
+	b.WriteString("</" + teamsTag + ">")
+}
+
+func (conv *matrixHTMLConverter) renderChildren(b *strings.Builder, node *nethtml.Node, inPre bool) {
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		conv.render(b, child, inPre)
+	}
+}
+
+func (conv *matrixHTMLConverter) renderLink(b *strings.Builder, node *nethtml.Node, inPre bool) {
+	href := strings.TrimSpace(nodeAttr(node, "href"))
+	if conv.resolve != nil && href != "" {
+		if mri := NormalizeTeamsUserID(conv.resolve(href)); mri != "" {
+			name := normalizePlainText(nodeText(node))
+			itemID := strconv.Itoa(len(conv.mentions))
+			b.WriteString(FormatMentionSpan(itemID, html.EscapeString(name)))
+			conv.mentions = append(conv.mentions, MessageMention{ItemID: itemID, MRI: mri, DisplayName: name})
+			return
+		}
+	}
+	safeHref, ok := sanitizeHref(href)
+	if !ok || strings.HasPrefix(strings.ToLower(safeHref), "matrix:") {
+		// Unresolvable Matrix links (rooms, events, non-ghost users) keep only their text.
+		conv.renderChildren(b, node, inPre)
+		return
+	}
+	b.WriteString(`<a href="`)
+	b.WriteString(html.EscapeString(safeHref))
+	b.WriteString(`">`)
+	conv.renderChildren(b, node, inPre)
+	b.WriteString("</a>")
+}
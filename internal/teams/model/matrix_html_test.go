@@ -0,0 +1,51 @@
+package model
+
+import "testing"
+
+func TestConvertMatrixHTMLKeepsFormatting(t *testing.T) {
+	got, mentions := ConvertMatrixHTML(
+		"hello <strong>bold</strong> <del>gone</del>\n<ul><li><a href=\"https://example.com/?a=1&amp;b=2\">link</a></li></ul>"+
+			"<pre><code class=\"language-go\">a &lt; b\nc</code></pre>tail",
+		nil,
+	)
+	want := `<p>hello <strong>bold</strong> <s>gone</s></p><ul><li><a href="https://example.com/?a=1&amp;b=2">link</a></li></ul>` +
+		"<pre><code>a &lt; b\nc</code></pre><p>tail</p>"
+	if got != want {
+		t.Fatalf("unexpected html:\nwant: %s\ngot:  %s", want, got)
+	}
+	if mentions != nil {
+		t.Fatalf("unexpected mentions: %#v", mentions)
+	}
+}
+
+func TestConvertMatrixHTMLSanitizes(t *testing.T) {
+	got, _ := ConvertMatrixHTML(
+		`<mx-reply><blockquote>quoted</blockquote></mx-reply><p onclick="x()">hi <a href="javascript:alert(1)">there</a>`+
+			`<img src="mxc://example.org/abc" alt="cat"><script>alert(1)</script><font color="red">red</font></p>`,
+		nil,
+	)
+	if got != "<p>hi therecatred</p>" {
+		t.Fatalf("unexpected html: %q", got)
+	}
+}
+
+func TestConvertMatrixHTMLMentions(t *testing.T) {
+	resolve := func(href string) string {
+		if href == "https://matrix.to/#/@teams_alice:example.org" {
+			return "8:live:alice"
+		}
+		return ""
+	}
+	got, mentions := ConvertMatrixHTML(
+		`<a href="https://matrix.to/#/@teams_alice:example.org">Alice</a>: see <a href="https://matrix.to/#/@bob:example.org">Bob</a>`,
+		resolve,
+	)
+	want := `<p><span itemscope="" itemtype="http://schema.skype.com/Mention" itemid="0">Alice</span>: see ` +
+		`<a href="https://matrix.to/#/@bob:example.org">Bob</a></p>`
+	if got != want {
+		t.Fatalf("unexpected html:\nwant: %s\ngot:  %s", want, got)
+	}
+	if len(mentions) != 1 || mentions[0] != (MessageMention{ItemID: "0", MRI: "8:live:alice", DisplayName: "Alice"}) {
+		t.Fatalf("unexpected mentions: %#v", mentions)
+	}
+}
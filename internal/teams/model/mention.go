@@ -0,0 +1,99 @@
+package model
+
+import (
+	"encoding/json"
+	"html"
+	"strings"
+)
+
+const mentionItemType = "http://schema.skype.com/Mention"
+
+// MentionPillResolver maps the itemid of a Teams mention span to the link of
+// the pill it should be rendered as (usually a matrix.to URL), or reports false
+// to keep the mention as plain text.
+type MentionPillResolver func(itemID string) (href string, ok bool)
+
+type MessageMention struct {
+	ItemID      string
+	MRI         string
+	DisplayName string
+}
+
+// ExtractMentions reads properties.mentions, which Teams sends either as a
+// JSON-encoded string or as a plain array.
+func ExtractMentions(properties json.RawMessage) []MessageMention {
+	if len(properties) == 0 {
+		return nil
+	}
+	var payload struct {
+		Mentions json.RawMessage `json:"mentions"`
+	}
+	if err := json.Unmarshal(properties, &payload); err != nil {
+		return nil
+	}
+	raw := payload.Mentions
+	var encoded string
+	if err := json.Unmarshal(raw, &encoded); err == nil {
+		raw = json.RawMessage(encoded)
+	}
+	var entries []struct {
+		ItemID      json.RawMessage `json:"itemid"`
+		MRI         string          `json:"mri"`
+		DisplayName string          `json:"displayName"`
+	}
+	if err := json.Unmarshal(raw, &entries); err != nil {
+		return nil
+	}
+	mentions := make([]MessageMention, 0, len(entries))
+	for _, entry := range entries {
+		itemID := strings.Trim(strings.TrimSpace(string(entry.ItemID)), `"`)
+		mri := NormalizeTeamsUserID(entry.MRI)
+		if itemID == "" || mri == "" {
+			continue
+		}
+		mentions = append(mentions, MessageMention{
+			ItemID:      itemID,
+			MRI:         mri,
+			DisplayName: strings.TrimSpace(entry.DisplayName),
+		})
+	}
+	if len(mentions) == 0 {
+		return nil
+	}
+	return mentions
+}
+
+// FormatMentionSpan renders the content markup Teams pairs with a properties.mentions entry.
+func FormatMentionSpan(itemID string, escapedText string) string {
+	return `<span itemscope="" itemtype="` + mentionItemType + `" itemid="` + html.EscapeString(itemID) + `">` + escapedText + `</span>`
+}
+
+// EncodeMentionsProperty is the inverse of ExtractMentions: it returns the
+// JSON-encoded string Teams expects in properties.mentions.
+func EncodeMentionsProperty(mentions []MessageMention) string {
+	if len(mentions) == 0 {
+		return ""
+	}
+	type entry struct {
+		Type        string `json:"@type"`
+		ItemID      string `json:"itemid"`
+		MRI         string `json:"mri"`
+		MentionType string `json:"mentionType"`
+		DisplayName string `json:"displayName"`
+	}
+	entries := make([]entry, 0, len(mentions))
+	for _, mention := range mentions {
+		entries = append(entries, entry{
+			Type:        mentionItemType,
+			ItemID:      mention.ItemID,
+			MRI:         mention.MRI,
+			MentionType: "person",
+			DisplayName: mention.DisplayName,
+		})
+	}
+	encoded, err := json.Marshal(entries)
+	if err != nil {
+		return ""
+	}
+	return string(encoded)
+}
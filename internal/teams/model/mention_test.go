@@ -0,0 +1,45 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestExtractMentionsFromEncodedString(t *testing.T) {
+	properties := json.RawMessage(`{"mentions":"[{\"@type\":\"http://schema.skype.com/Mention\",\"itemid\":0,\"mri\":\"8:live:alice\",\"mentionType\":\"person\",\"displayName\":\"Alice\"},{\"itemid\":\"1\",\"mri\":\"\"}]"}`)
+	mentions := ExtractMentions(properties)
+	if len(mentions) != 1 {
+		t.Fatalf("unexpected mentions: %#v", mentions)
+	}
+	if mentions[0] != (MessageMention{ItemID: "0", MRI: "8:live:alice", DisplayName: "Alice"}) {
+		t.Fatalf("unexpected mention: %#v", mentions[0])
+	}
+}
+
+func TestExtractMentionsFromArray(t *testing.T) {
+	properties := json.RawMessage(`{"mentions":[{"itemid":2,"mri":"8:live:bob","displayName":"Bob"}]}`)
+	mentions := ExtractMentions(properties)
+	if len(mentions) != 1 || mentions[0].ItemID != "2" || mentions[0].MRI != "8:live:bob" {
+		t.Fatalf("unexpected mentions: %#v", mentions)
+	}
+	if ExtractMentions(json.RawMessage(`{"files":"[]"}`)) != nil {
+		t.Fatalf("expected nil without mentions")
+	}
+}
+
+func TestNormalizeMessageBodyMentions(t *testing.T) {
+	raw := `<p>hi <span itemscope="" itemtype="http://schema.skype.com/Mention" itemid="0">Alice</span>!</p>`
+	content := NormalizeMessageBody(raw)
+	if content.Body != "hi Alice!" || content.FormattedBody != "<p>hi Alice!</p>" {
+		t.Fatalf("expected an unresolved mention to stay plain text, got %q %q", content.Body, content.FormattedBody)
+	}
+	content = NormalizeMessageBodyWithMentions(raw, func(itemID string) (string, bool) {
+		return "https://matrix.to/#/@alice:example.org", itemID == "0"
+	})
+	if content.Body != "hi Alice!" {
+		t.Fatalf("unexpected plaintext body: %q", content.Body)
+	}
+	if content.FormattedBody != `<p>hi <a href="https://matrix.to/#/@alice:example.org">Alice</a>!</p>` {
+		t.Fatalf("unexpected formatted body: %q", content.FormattedBody)
+	}
+}
@@ -16,17 +16,80 @@ type RemoteMessage struct {
 	IMDisplayName    string
 	TokenDisplayName string
 	Timestamp        time.Time
+	MessageType      string
+	Kind             MessageKind
 	Body             string
 	FormattedBody    string
-	GIFs             []TeamsGIF
-	PropertiesFiles  string
-	Reactions        []MessageReaction
+	// ContentHTML is the content Body and FormattedBody were rendered from.
+	ContentHTML     string
+	GIFs            []TeamsGIF
+	InlineImages    []InlineImage
+	ReplyTo         *MessageReply
+	ForwardedFrom   *MessageForward
+	Mentions        []MessageMention
+	PropertiesFiles string
+	Reactions       []MessageReaction
+	// EditedMessageID is the skypeeditedid of the message this one replaces, if any.
+	EditedMessageID string
+	EditTime        time.Time
+	DeleteTime      time.Time
+	// ThreadActivity is set for supported ThreadActivity/* messages.
+	ThreadActivity *ThreadActivity
+	// Audio is set for RichText/Media_AudioMsg voice clips.
+	Audio *AudioMessage
+	// Call is set for Event/Call messages.
+	Call *CallEvent
+	// Cards are adaptive or hero cards posted by bots, meeting invites and Forms.
+	Cards []Card
+	// Poll is set when one of the cards is a Forms poll.
+	Poll *Poll
+	// Location is set for RichText/Location messages.
+	Location *Location
+	// Contacts are set for RichText/Contacts messages.
+	Contacts []Contact
+	// LinkPreviews are the URL previews Teams generated for links in the body.
+	LinkPreviews []LinkPreview
+	// Subject is the optional subject line set by the sender.
+	Subject    string
+	Importance MessageImportance
+}
+
+func (m RemoteMessage) IsEdited() bool {
+	return !m.EditTime.IsZero()
+}
+
+// IsDeleted reports whether Teams marked the message as deleted, either via
+// deletetime or by editing a text message down to no content at all. Other
+// kinds keep their content outside the body, so an empty body means nothing.
+func (m RemoteMessage) IsDeleted() bool {
+	if !m.DeleteTime.IsZero() {
+		return true
+	}
+	return m.Kind == MessageKindText &&
+		m.IsEdited() &&
+		strings.TrimSpace(m.Body) == "" &&
+		strings.TrimSpace(m.FormattedBody) == "" &&
+		len(m.GIFs) == 0 &&
+		len(m.InlineImages) == 0 &&
+		len(m.Cards) == 0 &&
+		strings.TrimSpace(m.PropertiesFiles) == ""
 }
 
 type MessageContent struct {
 	Body          string
 	FormattedBody string
 	GIFs          []TeamsGIF
+	InlineImages  []InlineImage
+	ReplyTo       *MessageReply
+	ForwardedFrom *MessageForward
+}
+
+// MessageReply is the quoted original of a Teams reply.
+type MessageReply struct {
+	MessageID  string
+	SenderID   string
+	SenderName string
+	Preview    string
 }
 
 type MessageReaction struct {
@@ -44,28 +107,35 @@ func ExtractBody(content json.RawMessage) string {
 }
 
 func ExtractContent(content json.RawMessage) MessageContent {
-	if len(content) == 0 {
+	raw, ok := ContentHTML(content)
+	if !ok {
 		return MessageContent{}
 	}
+	normalized := NormalizeMessageBody(raw)
+	if gifs, ok := ParseGIFsFromHTML(raw); ok {
+		normalized.GIFs = gifs
+	}
+	normalized.InlineImages = ParseInlineImagesFromHTML(raw)
+	return normalized
+}
+
+// ContentHTML unwraps the message content, which Teams sends either as a JSON
+// string or as an object with a text field.
+func ContentHTML(content json.RawMessage) (string, bool) {
+	if len(content) == 0 {
+		return "", false
+	}
 	var plain string
 	if err := json.Unmarshal(content, &plain); err == nil {
-		normalized := NormalizeMessageBody(plain)
-		if gifs, ok := ParseGIFsFromHTML(plain); ok {
-			normalized.GIFs = gifs
-		}
-		return normalized
+		return plain, true
 	}
 	var obj struct {
 		Text string `json:"text"`
 	}
 	if err := json.Unmarshal(content, &obj); err == nil {
-		normalized := NormalizeMessageBody(obj.Text)
-		if gifs, ok := ParseGIFsFromHTML(obj.Text); ok {
-			normalized.GIFs = gifs
-		}
-		return normalized
+		return obj.Text, true
 	}
-	return MessageContent{}
+	return "", false
 }
 
 func ExtractSenderID(raw json.RawMessage) string {
@@ -151,7 +221,7 @@ func ExtractReactions(properties json.RawMessage) []MessageReaction {
 			}
 			users = append(users, MessageReactionUser{
 				MRI:    mri,
-				TimeMS: parseReactionTime(user.Time),
+				TimeMS: parseTimeMS(user.Time),
 			})
 		}
 		if len(users) == 0 {
@@ -168,7 +238,30 @@ func ExtractReactions(properties json.RawMessage) []MessageReaction {
 	return reactions
 }
 
-func parseReactionTime(raw json.RawMessage) int64 {
+func ExtractEditTime(properties json.RawMessage) time.Time {
+	return extractPropertyTime(properties, "edittime")
+}
+
+func ExtractDeleteTime(properties json.RawMessage) time.Time {
+	return extractPropertyTime(properties, "deletetime")
+}
+
+func extractPropertyTime(properties json.RawMessage, key string) time.Time {
+	if len(properties) == 0 {
+		return time.Time{}
+	}
+	var payload map[string]json.RawMessage
+	if err := json.Unmarshal(properties, &payload); err != nil {
+		return time.Time{}
+	}
+	ms := parseTimeMS(payload[key])
+	if ms <= 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms).UTC()
+}
+
+func parseTimeMS(raw json.RawMessage) int64 {
 	if len(raw) == 0 {
 		return 0
 	}
@@ -1,14 +1,18 @@
 package model
 
 import (
+	"fmt"
 	"html"
 	"net/url"
 	"regexp"
+	"strconv"
 	"strings"
 
 	nethtml "golang.org/x/net/html"
 )
 
+const replyItemType = "http://schema.skype.com/Reply"
+
 var htmlTagPattern = regexp.MustCompile(`(?i)<\s*/?\s*[a-z][^>]*>`)
 var manyNewlinesPattern = regexp.MustCompile(`\n{3,}`)
 
@@ -18,7 +22,15 @@ var allowedFormattedTags = map[string]bool{
 	"blockquote": true,
 	"br":         true,
 	"code":       true,
+	"del":        true,
 	"em":         true,
+	"h1":         true,
+	"h2":         true,
+	"h3":         true,
+	"h4":         true,
+	"h5":         true,
+	"h6":         true,
+	"hr":         true,
 	"i":          true,
 	"li":         true,
 	"ol":         true,
@@ -26,10 +38,20 @@ var allowedFormattedTags = map[string]bool{
 	"pre":        true,
 	"s":          true,
 	"strong":     true,
+	"sub":        true,
+	"sup":        true,
+	"table":      true,
+	"tbody":      true,
+	"td":         true,
+	"th":         true,
+	"thead":      true,
+	"tr":         true,
 	"u":          true,
 	"ul":         true,
 }
 
+var codeLanguagePattern = regexp.MustCompile(`^[a-z0-9_+#.-]+$`)
+
 var blockTags = map[string]bool{
 	"blockquote": true,
 	"div":        true,
@@ -39,15 +61,24 @@ var blockTags = map[string]bool{
 	"h4":         true,
 	"h5":         true,
 	"h6":         true,
+	"hr":         true,
 	"li":         true,
 	"ol":         true,
 	"p":          true,
 	"pre":        true,
 	"section":    true,
+	"table":      true,
+	"tr":         true,
 	"ul":         true,
 }
 
 func NormalizeMessageBody(raw string) MessageContent {
+	return NormalizeMessageBodyWithMentions(raw, nil)
+}
+
+// NormalizeMessageBodyWithMentions is NormalizeMessageBody with the mentions
+// pill resolves rendered as pills. Other mentions are kept as plain text.
+func NormalizeMessageBodyWithMentions(raw string, pill MentionPillResolver) MessageContent {
 	normalized := normalizePlainText(html.UnescapeString(raw))
 	if normalized == "" {
 		return MessageContent{}
@@ -56,42 +87,48 @@ func NormalizeMessageBody(raw string) MessageContent {
 		return MessageContent{Body: normalized}
 	}
 
-	body, formatted, ok := normalizeHTMLFragment(raw)
+	content, ok := normalizeHTMLFragment(raw, pill)
 	if !ok {
 		return MessageContent{Body: normalized}
 	}
-	if body == "" {
-		return MessageContent{}
-	}
-	if formatted == "" {
-		return MessageContent{Body: body}
-	}
-	return MessageContent{
-		Body:          body,
-		FormattedBody: formatted,
+	if content.Body == "" {
+		content.FormattedBody = ""
 	}
+	return content
 }
 
 func looksLikeHTML(value string) bool {
 	return htmlTagPattern.MatchString(value)
 }
 
-func normalizeHTMLFragment(value string) (body string, formatted string, ok bool) {
+func normalizeHTMLFragment(value string, pill MentionPillResolver) (MessageContent, bool) {
 	doc, err := nethtml.Parse(strings.NewReader("<div>" + value + "</div>"))
 	if err != nil {
-		return "", "", false
+		return MessageContent{}, false
 	}
 	wrapper := findWrapperDiv(doc)
 	if wrapper == nil {
-		return "", "", false
+		return MessageContent{}, false
+	}
+	var content MessageContent
+	// The quoted original is carried as a reply relation instead of inline text.
+	if quote := findReplyQuote(wrapper); quote != nil {
+		content.ReplyTo = parseReplyQuote(quote)
+		quote.Parent.RemoveChild(quote)
 	}
+	// Likewise the forward header, while the forwarded content stays in the body.
+	if block := findForwardBlock(wrapper); block != nil {
+		content.ForwardedFrom = extractForward(block)
+	}
+	replaceEmoticons(wrapper)
+	mergeSplitMentions(wrapper, pill)
 
 	var nodes []*nethtml.Node
 	for child := wrapper.FirstChild; child != nil; child = child.NextSibling {
 		nodes = append(nodes, child)
 	}
 	if len(nodes) == 0 {
-		return "", "", true
+		return content, true
 	}
 
 	var plainBuilder strings.Builder
@@ -99,17 +136,161 @@ func normalizeHTMLFragment(value string) (body string, formatted string, ok bool
 	var renderedTag bool
 	for _, node := range nodes {
 		renderPlainNode(&plainBuilder, node)
-		if renderFormattedNode(&htmlBuilder, node) {
+		if renderFormattedNode(&htmlBuilder, node, pill) {
 			renderedTag = true
 		}
 	}
 
-	plain := normalizePlainText(plainBuilder.String())
-	sanitized := normalizeSanitizedHTML(htmlBuilder.String())
-	if !renderedTag {
-		sanitized = ""
+	content.Body = normalizePlainText(plainBuilder.String())
+	if renderedTag {
+		content.FormattedBody = normalizeSanitizedHTML(htmlBuilder.String())
+	}
+	return content, true
+}
+
+// mergeSplitMentions joins the mention spans Teams emits for each word of a
+// multi-word name, so they become a single pill.
+func mergeSplitMentions(node *nethtml.Node, pill MentionPillResolver) {
+	if pill == nil {
+		return
+	}
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		href, ok := mentionPill(child, pill)
+		if !ok {
+			mergeSplitMentions(child, pill)
+			continue
+		}
+		for {
+			next := child.NextSibling
+			var gap *nethtml.Node
+			if next != nil && next.Type == nethtml.TextNode && strings.TrimSpace(next.Data) == "" {
+				gap, next = next, next.NextSibling
+			}
+			if nextHref, nextOK := mentionPill(next, pill); !nextOK || nextHref != href {
+				break
+			}
+			if gap != nil {
+				node.RemoveChild(gap)
+				child.AppendChild(gap)
+			}
+			for next.FirstChild != nil {
+				moved := next.FirstChild
+				next.RemoveChild(moved)
+				child.AppendChild(moved)
+			}
+			node.RemoveChild(next)
+		}
+	}
+}
+
+// mentionPill resolves a Teams mention span to the link of its pill.
+func mentionPill(node *nethtml.Node, pill MentionPillResolver) (string, bool) {
+	if pill == nil || node == nil || node.Type != nethtml.ElementNode || !strings.EqualFold(node.Data, "span") ||
+		!strings.EqualFold(strings.TrimSpace(nodeAttr(node, "itemtype")), mentionItemType) {
+		return "", false
+	}
+	itemID := strings.TrimSpace(nodeAttr(node, "itemid"))
+	if itemID == "" {
+		return "", false
+	}
+	return pill(itemID)
+}
+
+// replaceEmoticons swaps Teams <emoji> and legacy <ss> tags for plain Unicode
+// text, so both the plain and the HTML output carry the emoji itself.
+func replaceEmoticons(node *nethtml.Node) {
+	for child := node.FirstChild; child != nil; {
+		next := child.NextSibling
+		if child.Type == nethtml.ElementNode {
+			var replacement string
+			var ok bool
+			switch strings.ToLower(child.Data) {
+			case "emoji":
+				replacement, ok = emojiTagText(child)
+			case "ss":
+				replacement, ok = EmoticonKeyToEmoji(nodeAttr(child, "type"))
+				if !ok {
+					replacement, ok = nodeText(child), true
+				}
+			}
+			if ok {
+				node.InsertBefore(&nethtml.Node{Type: nethtml.TextNode, Data: replacement}, child)
+				node.RemoveChild(child)
+			} else {
+				replaceEmoticons(child)
+			}
+		}
+		child = next
+	}
+}
+
+func emojiTagText(node *nethtml.Node) (string, bool) {
+	if alt := strings.TrimSpace(nodeAttr(node, "alt")); alt != "" {
+		return alt, true
+	}
+	if emoji, ok := EmoticonKeyToEmoji(nodeAttr(node, "id")); ok {
+		return emoji, true
+	}
+	return nodeText(node), true
+}
+
+func findReplyQuote(node *nethtml.Node) *nethtml.Node {
+	if node.Type == nethtml.ElementNode && strings.EqualFold(node.Data, "blockquote") &&
+		strings.EqualFold(strings.TrimSpace(nodeAttr(node, "itemtype")), replyItemType) {
+		return node
+	}
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if found := findReplyQuote(child); found != nil {
+			return found
+		}
 	}
-	return plain, sanitized, true
+	return nil
+}
+
+func parseReplyQuote(quote *nethtml.Node) *MessageReply {
+	reply := &MessageReply{MessageID: strings.TrimSpace(nodeAttr(quote, "itemid"))}
+	if reply.MessageID == "" {
+		return nil
+	}
+	var previewBuilder strings.Builder
+	var walk func(node *nethtml.Node)
+	walk = func(node *nethtml.Node) {
+		if node.Type == nethtml.ElementNode {
+			switch strings.ToLower(strings.TrimSpace(nodeAttr(node, "itemprop"))) {
+			case "mri":
+				reply.SenderID = NormalizeTeamsUserID(nodeAttr(node, "itemid"))
+				reply.SenderName = normalizePlainText(nodeText(node))
+				return
+			case "time":
+				return
+			case "preview":
+				previewBuilder.Reset()
+				renderPlainNode(&previewBuilder, node)
+				reply.Preview = normalizePlainText(previewBuilder.String())
+				return
+			}
+		}
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(quote)
+	return reply
+}
+
+func nodeAttr(node *nethtml.Node, key string) string {
+	for _, attr := range node.Attr {
+		if strings.EqualFold(attr.Key, key) {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+func nodeText(node *nethtml.Node) string {
+	var builder strings.Builder
+	renderPlainNode(&builder, node)
+	return builder.String()
 }
 
 func renderPlainNode(builder *strings.Builder, node *nethtml.Node) {
@@ -128,6 +309,9 @@ func renderPlainNode(builder *strings.Builder, node *nethtml.Node) {
 		if blockTags[tag] {
 			appendPlainNewline(builder)
 		}
+		if (tag == "td" || tag == "th") && previousElementSibling(node) != nil {
+			builder.WriteString(" | ")
+		}
 		for child := node.FirstChild; child != nil; child = child.NextSibling {
 			renderPlainNode(builder, child)
 		}
@@ -141,7 +325,7 @@ func renderPlainNode(builder *strings.Builder, node *nethtml.Node) {
 	}
 }
 
-func renderFormattedNode(builder *strings.Builder, node *nethtml.Node) bool {
+func renderFormattedNode(builder *strings.Builder, node *nethtml.Node, pill MentionPillResolver) bool {
 	switch node.Type {
 	case nethtml.TextNode:
 		builder.WriteString(html.EscapeString(node.Data))
@@ -151,14 +335,38 @@ func renderFormattedNode(builder *strings.Builder, node *nethtml.Node) bool {
 		if isUnsafeTag(tag) {
 			return false
 		}
-		if tag == "br" {
-			builder.WriteString("<br>")
+		if tag == "br" || tag == "hr" {
+			builder.WriteString("<" + tag + ">")
+			return true
+		}
+		if tag == "codeblock" {
+			renderCodeBlock(builder, node)
+			return true
+		}
+		if href, ok := mentionPill(node, pill); ok {
+			builder.WriteString(`<a href="`)
+			builder.WriteString(html.EscapeString(href))
+			builder.WriteString(`">`)
+			for child := node.FirstChild; child != nil; child = child.NextSibling {
+				renderFormattedNode(builder, child, pill)
+			}
+			builder.WriteString("</a>")
 			return true
 		}
+		if tag == "span" {
+			if attrs := colorAttrs(nodeAttr(node, "style")); attrs != "" {
+				builder.WriteString("<span" + attrs + ">")
+				for child := node.FirstChild; child != nil; child = child.NextSibling {
+					renderFormattedNode(builder, child, pill)
+				}
+				builder.WriteString("</span>")
+				return true
+			}
+		}
 		if !allowedFormattedTags[tag] {
 			var rendered bool
 			for child := node.FirstChild; child != nil; child = child.NextSibling {
-				if renderFormattedNode(builder, child) {
+				if renderFormattedNode(builder, child, pill) {
 					rendered = true
 				}
 			}
@@ -174,10 +382,15 @@ func renderFormattedNode(builder *strings.Builder, node *nethtml.Node) bool {
 				builder.WriteByte('"')
 			}
 		}
+		if tag == "code" {
+			if lang := codeLanguage(node); lang != "" {
+				builder.WriteString(` class="language-` + lang + `"`)
+			}
+		}
 		builder.WriteByte('>')
 
 		for child := node.FirstChild; child != nil; child = child.NextSibling {
-			renderFormattedNode(builder, child)
+			renderFormattedNode(builder, child, pill)
 		}
 
 		builder.WriteString("</")
@@ -187,7 +400,7 @@ func renderFormattedNode(builder *strings.Builder, node *nethtml.Node) bool {
 	default:
 		var rendered bool
 		for child := node.FirstChild; child != nil; child = child.NextSibling {
-			if renderFormattedNode(builder, child) {
+			if renderFormattedNode(builder, child, pill) {
 				rendered = true
 			}
 		}
@@ -195,6 +408,92 @@ func renderFormattedNode(builder *strings.Builder, node *nethtml.Node) bool {
 	}
 }
 
+// renderCodeBlock maps Teams' <codeblock class="Go"> onto the Matrix
+// <pre><code class="language-go"> form, dropping any highlighting markup.
+func renderCodeBlock(builder *strings.Builder, node *nethtml.Node) {
+	builder.WriteString("<pre><code")
+	if lang := strings.ToLower(strings.TrimSpace(nodeAttr(node, "class"))); codeLanguagePattern.MatchString(lang) {
+		builder.WriteString(` class="language-` + lang + `"`)
+	}
+	builder.WriteByte('>')
+	builder.WriteString(html.EscapeString(strings.Trim(nodeText(node), "\n")))
+	builder.WriteString("</code></pre>")
+}
+
+// codeLanguage reads a language-x class from a <code> or its parent <pre>.
+func codeLanguage(node *nethtml.Node) string {
+	classes := strings.Fields(nodeAttr(node, "class"))
+	if node.Parent != nil && strings.EqualFold(node.Parent.Data, "pre") {
+		classes = append(classes, strings.Fields(nodeAttr(node.Parent, "class"))...)
+	}
+	for _, class := range classes {
+		lang, ok := strings.CutPrefix(strings.ToLower(class), "language-")
+		if ok && codeLanguagePattern.MatchString(lang) {
+			return lang
+		}
+	}
+	return ""
+}
+
+// colorAttrs converts the inline color styles Teams uses for colored and
+// highlighted text into Matrix data-mx-color/data-mx-bg-color attributes.
+func colorAttrs(style string) string {
+	var attrs string
+	for _, decl := range strings.Split(style, ";") {
+		key, value, ok := strings.Cut(decl, ":")
+		if !ok {
+			continue
+		}
+		color, ok := parseCSSColor(value)
+		if !ok {
+			continue
+		}
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "color":
+			attrs += ` data-mx-color="` + color + `"`
+		case "background-color", "background":
+			attrs += ` data-mx-bg-color="` + color + `"`
+		}
+	}
+	return attrs
+}
+
+var hexColorPattern = regexp.MustCompile(`^#([0-9a-f]{3}|[0-9a-f]{6})$`)
+var rgbColorPattern = regexp.MustCompile(`^rgba?\(\s*(\d{1,3})\s*,\s*(\d{1,3})\s*,\s*(\d{1,3})\s*(?:,[^)]*)?\)$`)
+
+func parseCSSColor(value string) (string, bool) {
+	value = strings.ToLower(strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(value), "!important")))
+	if hexColorPattern.MatchString(value) {
+		if len(value) == 4 {
+			value = "#" + strings.Repeat(value[1:2], 2) + strings.Repeat(value[2:3], 2) + strings.Repeat(value[3:4], 2)
+		}
+		return value, true
+	}
+	match := rgbColorPattern.FindStringSubmatch(value)
+	if match == nil {
+		return "", false
+	}
+	var hex strings.Builder
+	hex.WriteByte('#')
+	for _, component := range match[1:] {
+		n, err := strconv.Atoi(component)
+		if err != nil || n > 255 {
+			return "", false
+		}
+		fmt.Fprintf(&hex, "%02x", n)
+	}
+	return hex.String(), true
+}
+
+func previousElementSibling(node *nethtml.Node) *nethtml.Node {
+	for sibling := node.PrevSibling; sibling != nil; sibling = sibling.PrevSibling {
+		if sibling.Type == nethtml.ElementNode {
+			return sibling
+		}
+	}
+	return nil
+}
+
 func findWrapperDiv(node *nethtml.Node) *nethtml.Node {
 	if node == nil {
 		return nil
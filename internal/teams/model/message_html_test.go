@@ -41,3 +41,81 @@ func TestNormalizeMessageBodyUnsupportedTagFallsBackToPlain(t *testing.T) {
 		t.Fatalf("expected empty formatted body, got %q", content.FormattedBody)
 	}
 }
+
+func TestNormalizeMessageBodyExtractsReplyQuote(t *testing.T) {
+	raw := `<blockquote itemscope="" itemtype="http://schema.skype.com/Reply" itemid="1700000000000">` +
+		`<strong itemprop="mri" itemid="8:live:alice">Alice</strong><span itemprop="time" itemid="1700000000000"></span>` +
+		`<p itemprop="preview">original text</p></blockquote><p>my reply</p>`
+	content := NormalizeMessageBody(raw)
+	if content.Body != "my reply" {
+		t.Fatalf("unexpected plaintext body: %q", content.Body)
+	}
+	if content.FormattedBody != "<p>my reply</p>" {
+		t.Fatalf("unexpected formatted body: %q", content.FormattedBody)
+	}
+	want := MessageReply{MessageID: "1700000000000", SenderID: "8:live:alice", SenderName: "Alice", Preview: "original text"}
+	if content.ReplyTo == nil || *content.ReplyTo != want {
+		t.Fatalf("unexpected reply: %#v", content.ReplyTo)
+	}
+}
+
+func TestNormalizeMessageBodyKeepsPlainBlockquote(t *testing.T) {
+	content := NormalizeMessageBody("<blockquote>quoted</blockquote><p>text</p>")
+	if content.ReplyTo != nil {
+		t.Fatalf("expected no reply, got %#v", content.ReplyTo)
+	}
+	if content.FormattedBody != "<blockquote>quoted</blockquote><p>text</p>" {
+		t.Fatalf("unexpected formatted body: %q", content.FormattedBody)
+	}
+}
+
+func TestNormalizeMessageBodyGolden(t *testing.T) {
+	cases := []struct {
+		name      string
+		raw       string
+		body      string
+		formatted string
+	}{
+		{
+			name:      "table",
+			raw:       `<table itemprop="copy-paste-table"><tbody><tr><th>Name</th><th>Qty</th></tr><tr><td>Apples</td><td>3</td></tr></tbody></table>`,
+			body:      "Name | Qty\nApples | 3",
+			formatted: `<table><tbody><tr><th>Name</th><th>Qty</th></tr><tr><td>Apples</td><td>3</td></tr></tbody></table>`,
+		},
+		{
+			name:      "headings and rule",
+			raw:       `<h1 style="font-size:2em">Title</h1><hr><h3>Sub</h3><p>x<sup>2</sup> H<sub>2</sub>O</p>`,
+			body:      "Title\nSub\nx2 H2O",
+			formatted: `<h1>Title</h1><hr><h3>Sub</h3><p>x<sup>2</sup> H<sub>2</sub>O</p>`,
+		},
+		{
+			name:      "teams codeblock",
+			raw:       `<codeblock class="JavaScript"><code><span class="hljs-keyword">let</span> a = 1;<br>a &lt; 2</code></codeblock>`,
+			body:      "let a = 1;\na < 2",
+			formatted: "<pre><code class=\"language-javascript\">let a = 1;\na &lt; 2</code></pre>",
+		},
+		{
+			name:      "pre code language",
+			raw:       `<pre class="language-go"><code>fmt.Println()</code></pre><p><code class="evil">x</code></p>`,
+			body:      "fmt.Println()\nx",
+			formatted: `<pre><code class="language-go">fmt.Println()</code></pre><p><code>x</code></p>`,
+		},
+		{
+			name:      "colored spans",
+			raw:       `<p><span style="color:rgb(232, 17, 35);">red</span> <span style="background-color:#FF0">marked</span> <span style="font-size:20px">big</span></p>`,
+			body:      "red marked big",
+			formatted: `<p><span data-mx-color="#e81123">red</span> <span data-mx-bg-color="#ffff00">marked</span> big</p>`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			content := NormalizeMessageBody(tc.raw)
+			if content.Body != tc.body {
+				t.Errorf("unexpected plaintext body:\nwant: %q\ngot:  %q", tc.body, content.Body)
+			}
+			if content.FormattedBody != tc.formatted {
+				t.Errorf("unexpected formatted body:\nwant: %s\ngot:  %s", tc.formatted, content.FormattedBody)
+			}
+		})
+	}
+}
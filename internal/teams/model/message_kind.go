@@ -0,0 +1,56 @@
+package model
+
+import "strings"
+
+// MessageKind is the broad category of a Teams message, derived from its messagetype.
+type MessageKind int
+
+const (
+	// MessageKindText is the zero value so payloads without a messagetype keep
+	// being treated as chat text.
+	MessageKindText MessageKind = iota
+	MessageKindMedia
+	MessageKindControl
+	MessageKindThreadActivity
+	MessageKindCall
+	MessageKindUnknown
+)
+
+func (k MessageKind) String() string {
+	switch k {
+	case MessageKindText:
+		return "text"
+	case MessageKindMedia:
+		return "media"
+	case MessageKindControl:
+		return "control"
+	case MessageKindThreadActivity:
+		return "thread_activity"
+	case MessageKindCall:
+		return "call"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseMessageKind maps a Teams messagetype (e.g. "RichText/Html", "Control/Typing",
+// "ThreadActivity/AddMember", "Event/Call", "RichText/Media_Card") to a MessageKind.
+func ParseMessageKind(messageType string) MessageKind {
+	messageType = strings.TrimSpace(messageType)
+	lower := strings.ToLower(messageType)
+	switch {
+	case lower == "", lower == "text", lower == "richtext", lower == "richtext/html":
+		return MessageKindText
+	case strings.HasPrefix(lower, "richtext/media_"), lower == "richtext/uriobject",
+		lower == "richtext/location", lower == "richtext/contacts":
+		return MessageKindMedia
+	case strings.HasPrefix(lower, "control/"):
+		return MessageKindControl
+	case strings.HasPrefix(lower, "threadactivity/"):
+		return MessageKindThreadActivity
+	case lower == "event/call":
+		return MessageKindCall
+	default:
+		return MessageKindUnknown
+	}
+}
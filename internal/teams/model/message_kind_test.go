@@ -0,0 +1,27 @@
+package model
+
+import "testing"
+
+func TestParseMessageKind(t *testing.T) {
+	tests := map[string]MessageKind{
+		"":                           MessageKindText,
+		"Text":                       MessageKindText,
+		"RichText/Html":              MessageKindText,
+		"RichText/Media_GenericFile": MessageKindMedia,
+		"RichText/Media_Card":        MessageKindMedia,
+		"RichText/UriObject":         MessageKindMedia,
+		"RichText/Location":          MessageKindMedia,
+		"RichText/Contacts":          MessageKindMedia,
+		"Control/Typing":             MessageKindControl,
+		"Control/ClearTyping":        MessageKindControl,
+		"ThreadActivity/AddMember":   MessageKindThreadActivity,
+		"ThreadActivity/TopicUpdate": MessageKindThreadActivity,
+		"Event/Call":                 MessageKindCall,
+		"Something/New":              MessageKindUnknown,
+	}
+	for messageType, want := range tests {
+		if got := ParseMessageKind(messageType); got != want {
+			t.Fatalf("ParseMessageKind(%q) = %s, want %s", messageType, got, want)
+		}
+	}
+}
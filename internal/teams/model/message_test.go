@@ -49,3 +49,27 @@ func TestExtractSenderID(t *testing.T) {
 		}
 	}
 }
+
+func TestRemoteMessageIsDeleted(t *testing.T) {
+	editTime := time.UnixMilli(1700000000000).UTC()
+	cases := []struct {
+		name string
+		msg  RemoteMessage
+		want bool
+	}{
+		{name: "plain", msg: RemoteMessage{Body: "hi"}, want: false},
+		{name: "empty unedited", msg: RemoteMessage{}, want: false},
+		{name: "deletetime", msg: RemoteMessage{Body: "hi", DeleteTime: editTime}, want: true},
+		{name: "edited to empty", msg: RemoteMessage{EditTime: editTime}, want: true},
+		{name: "edited with files", msg: RemoteMessage{EditTime: editTime, PropertiesFiles: "[{}]"}, want: false},
+		{name: "edited call event", msg: RemoteMessage{Kind: MessageKindCall, EditTime: editTime}, want: false},
+		{name: "edited media", msg: RemoteMessage{Kind: MessageKindMedia, EditTime: editTime}, want: false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.msg.IsDeleted(); got != tc.want {
+				t.Fatalf("IsDeleted() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
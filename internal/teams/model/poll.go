@@ -0,0 +1,188 @@
+package model
+
+import (
+	"encoding/json"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Poll is a Forms poll posted as an adaptive card.
+type Poll struct {
+	// ID is the id of the card's choice set input.
+	ID            string
+	Question      string
+	Options       []PollOption
+	MaxSelections int
+	// Closed is set once the card no longer offers a way to vote.
+	Closed bool
+}
+
+type PollOption struct {
+	ID   string
+	Text string
+	// Votes is the count shown on result cards, or -1 when the card has none.
+	Votes int
+}
+
+// HasResults reports whether the card showed vote counts.
+func (p *Poll) HasResults() bool {
+	for _, option := range p.Options {
+		if option.Votes >= 0 {
+			return true
+		}
+	}
+	return false
+}
+
+var pollVotesPattern = regexp.MustCompile(`(?i)^(\d+)\s+votes?\b`)
+
+type pollScan struct {
+	question  string
+	firstText string
+	choiceSet *adaptiveElement
+	votes     []cardFact
+	canVote   bool
+}
+
+// ParsePoll finds a poll in the cards of a message. Open polls have an
+// Input.ChoiceSet with the options and a submit action; once closed, Forms
+// replaces the card with a fact set of "N votes" results. Cards with fewer
+// than two options are not treated as polls.
+func ParsePoll(cards []Card) *Poll {
+	for _, card := range cards {
+		if card.ContentType != AdaptiveCardContentType {
+			continue
+		}
+		var root adaptiveElement
+		if err := json.Unmarshal(card.Content, &root); err != nil {
+			continue
+		}
+		var scan pollScan
+		scan.elements(root.Body)
+		scan.actions(root.Actions)
+		if poll := scan.poll(); poll != nil {
+			return poll
+		}
+	}
+	return nil
+}
+
+func (s *pollScan) elements(elements []adaptiveElement) {
+	for i := range elements {
+		element := &elements[i]
+		switch element.Type {
+		case "TextBlock":
+			if s.firstText == "" {
+				s.firstText = strings.TrimSpace(element.Text)
+			}
+		case "Input.ChoiceSet":
+			if s.choiceSet == nil {
+				s.choiceSet = element
+				s.question = strings.TrimSpace(element.Label)
+			}
+		case "FactSet":
+			for _, fact := range element.Facts {
+				if pollVotesPattern.MatchString(strings.TrimSpace(fact.Value)) {
+					s.votes = append(s.votes, fact)
+				}
+			}
+		case "Container", "Column":
+			s.elements(element.Items)
+		case "ColumnSet":
+			s.elements(element.Columns)
+		case "ActionSet":
+			s.actions(element.Actions)
+		}
+	}
+}
+
+func (s *pollScan) actions(actions []adaptiveAction) {
+	for _, action := range actions {
+		if action.Type == "Action.Submit" || action.Type == "Action.Execute" {
+			s.canVote = true
+		}
+	}
+}
+
+func (s *pollScan) poll() *Poll {
+	poll := &Poll{Question: s.question}
+	if poll.Question == "" {
+		poll.Question = s.firstText
+	}
+	counts := make(map[string]int, len(s.votes))
+	for _, fact := range s.votes {
+		match := pollVotesPattern.FindStringSubmatch(strings.TrimSpace(fact.Value))
+		count, _ := strconv.Atoi(match[1])
+		counts[strings.ToLower(strings.TrimSpace(fact.Title))] = count
+	}
+	if s.choiceSet != nil {
+		poll.ID = strings.TrimSpace(s.choiceSet.ID)
+		for i, choice := range s.choiceSet.Choices {
+			text := strings.TrimSpace(choice.Title)
+			if text == "" {
+				continue
+			}
+			id := strings.TrimSpace(choice.Value)
+			if id == "" {
+				id = strconv.Itoa(i)
+			}
+			votes, ok := counts[strings.ToLower(text)]
+			if !ok {
+				votes = -1
+			}
+			poll.Options = append(poll.Options, PollOption{ID: id, Text: text, Votes: votes})
+		}
+		poll.MaxSelections = 1
+		if s.choiceSet.IsMultiSelect {
+			poll.MaxSelections = len(poll.Options)
+		}
+		poll.Closed = !s.canVote
+	} else {
+		for i, fact := range s.votes {
+			text := strings.TrimSpace(fact.Title)
+			if text == "" {
+				continue
+			}
+			poll.Options = append(poll.Options, PollOption{ID: strconv.Itoa(i), Text: text, Votes: counts[strings.ToLower(text)]})
+		}
+		poll.MaxSelections = 1
+		poll.Closed = true
+	}
+	if poll.Question == "" || len(poll.Options) < 2 {
+		return nil
+	}
+	return poll
+}
+
+// RenderPoll renders the question and options as plain text and Matrix HTML,
+// including vote counts when the card had them.
+func RenderPoll(poll *Poll) (text string, formatted string) {
+	label := "Poll"
+	if poll.Closed {
+		label = "Poll (closed)"
+	}
+	lines := []string{label + ": " + poll.Question}
+	items := make([]string, 0, len(poll.Options))
+	for i, option := range poll.Options {
+		line := strconv.Itoa(i+1) + ". " + option.Text
+		item := html.EscapeString(option.Text)
+		if option.Votes >= 0 {
+			line += " (" + option.VoteLabel() + ")"
+			item += " (" + option.VoteLabel() + ")"
+		}
+		lines = append(lines, line)
+		items = append(items, "<li>"+item+"</li>")
+	}
+	formatted = "<p><strong>" + label + ":</strong> " + html.EscapeString(poll.Question) + "</p><ol>" + strings.Join(items, "") + "</ol>"
+	return strings.Join(lines, "\n"), formatted
+}
+
+// VoteLabel formats the vote count, e.g. "3 votes".
+func (o PollOption) VoteLabel() string {
+	if o.Votes == 1 {
+		return "1 vote"
+	}
+	return strconv.Itoa(o.Votes) + " votes"
+}
@@ -0,0 +1,79 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const openPollCard = `{
+	"type": "AdaptiveCard",
+	"body": [
+		{"type": "TextBlock", "text": "Forms poll", "weight": "bolder"},
+		{"type": "Input.ChoiceSet", "id": "r1", "label": "Where should we eat?", "isMultiSelect": true, "choices": [
+			{"title": "Pizza", "value": "c1"},
+			{"title": "Sushi", "value": "c2"},
+			{"title": "Tacos", "value": "c3"}
+		]}
+	],
+	"actions": [{"type": "Action.Submit", "title": "Vote"}]
+}`
+
+const closedPollCard = `{
+	"type": "AdaptiveCard",
+	"body": [
+		{"type": "TextBlock", "text": "Where should we eat?"},
+		{"type": "FactSet", "facts": [
+			{"title": "Pizza", "value": "3 votes"},
+			{"title": "Sushi", "value": "1 vote"},
+			{"title": "Tacos", "value": "0 votes (0%)"}
+		]}
+	]
+}`
+
+func TestParsePollOpen(t *testing.T) {
+	poll := ParsePoll([]Card{{ContentType: AdaptiveCardContentType, Content: json.RawMessage(openPollCard)}})
+	if poll == nil {
+		t.Fatal("expected poll")
+	}
+	if poll.ID != "r1" || poll.Question != "Where should we eat?" || poll.MaxSelections != 3 || poll.Closed || poll.HasResults() {
+		t.Fatalf("unexpected poll: %#v", poll)
+	}
+	if len(poll.Options) != 3 || poll.Options[1] != (PollOption{ID: "c2", Text: "Sushi", Votes: -1}) {
+		t.Fatalf("unexpected options: %#v", poll.Options)
+	}
+}
+
+func TestParsePollClosedResults(t *testing.T) {
+	poll := ParsePoll([]Card{{ContentType: AdaptiveCardContentType, Content: json.RawMessage(closedPollCard)}})
+	if poll == nil {
+		t.Fatal("expected poll")
+	}
+	if poll.Question != "Where should we eat?" || !poll.Closed || !poll.HasResults() || len(poll.Options) != 3 {
+		t.Fatalf("unexpected poll: %#v", poll)
+	}
+	text, formatted := RenderPoll(poll)
+	if text != "Poll (closed): Where should we eat?\n1. Pizza (3 votes)\n2. Sushi (1 vote)\n3. Tacos (0 votes)" {
+		t.Fatalf("unexpected text: %q", text)
+	}
+	if formatted != "<p><strong>Poll (closed):</strong> Where should we eat?</p><ol><li>Pizza (3 votes)</li><li>Sushi (1 vote)</li><li>Tacos (0 votes)</li></ol>" {
+		t.Fatalf("unexpected html: %q", formatted)
+	}
+}
+
+func TestParsePollIgnoresOtherCards(t *testing.T) {
+	tests := []struct {
+		name string
+		card string
+	}{
+		{name: "Facts", card: `{"type":"AdaptiveCard","body":[{"type":"TextBlock","text":"Build"},{"type":"FactSet","facts":[{"title":"Status","value":"ok"},{"title":"Tests","value":"12"}]}]}`},
+		{name: "SingleChoice", card: `{"type":"AdaptiveCard","body":[{"type":"Input.ChoiceSet","label":"Q","choices":[{"title":"Only","value":"1"}]}]}`},
+		{name: "NoQuestion", card: `{"type":"AdaptiveCard","body":[{"type":"Input.ChoiceSet","choices":[{"title":"A"},{"title":"B"}]}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if poll := ParsePoll([]Card{{ContentType: AdaptiveCardContentType, Content: json.RawMessage(tt.card)}}); poll != nil {
+				t.Fatalf("unexpected poll: %#v", poll)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package model
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type ThreadActivityType string
+
+const (
+	ThreadActivityAddMember    ThreadActivityType = "AddMember"
+	ThreadActivityDeleteMember ThreadActivityType = "DeleteMember"
+	ThreadActivityTopicUpdate  ThreadActivityType = "TopicUpdate"
+	// ThreadActivityPictureUpdate has an empty Value when the picture was removed.
+	ThreadActivityPictureUpdate ThreadActivityType = "PictureUpdate"
+)
+
+type ThreadActivityMember struct {
+	ID          string
+	DisplayName string
+}
+
+// ThreadActivity is a parsed ThreadActivity/* system message.
+type ThreadActivity struct {
+	Type        ThreadActivityType
+	EventTime   time.Time
+	InitiatorID string
+	Targets     []ThreadActivityMember
+	// Value holds the new topic for TopicUpdate and the new picture URL for PictureUpdate.
+	Value string
+}
+
+type threadActivityXML struct {
+	EventTime      string   `xml:"eventtime"`
+	Initiator      string   `xml:"initiator"`
+	Targets        []string `xml:"target"`
+	DetailedTarget []struct {
+		ID           string `xml:"id"`
+		FriendlyName string `xml:"friendlyName"`
+	} `xml:"detailedtargetinfo"`
+	Value string `xml:"value"`
+}
+
+// ParseThreadActivity parses the XML content of a ThreadActivity/* message.
+// It returns nil for activity types the bridge doesn't handle.
+func ParseThreadActivity(messageType string, content json.RawMessage) *ThreadActivity {
+	activityType := ThreadActivityType(strings.TrimPrefix(strings.TrimSpace(messageType), "ThreadActivity/"))
+	switch activityType {
+	case ThreadActivityAddMember, ThreadActivityDeleteMember, ThreadActivityTopicUpdate, ThreadActivityPictureUpdate:
+	default:
+		return nil
+	}
+	var raw string
+	if err := json.Unmarshal(content, &raw); err != nil || strings.TrimSpace(raw) == "" {
+		return nil
+	}
+	var parsed threadActivityXML
+	if err := xml.Unmarshal([]byte(raw), &parsed); err != nil {
+		return nil
+	}
+
+	activity := &ThreadActivity{
+		Type:        activityType,
+		EventTime:   parseEventTime(parsed.EventTime),
+		InitiatorID: NormalizeTeamsUserID(parsed.Initiator),
+		Value:       strings.TrimSpace(parsed.Value),
+	}
+	if activityType == ThreadActivityPictureUpdate {
+		// Picture URLs are prefixed with "URL@".
+		activity.Value = strings.TrimSpace(strings.TrimPrefix(activity.Value, "URL@"))
+	}
+	names := make(map[string]string, len(parsed.DetailedTarget))
+	for _, detail := range parsed.DetailedTarget {
+		if id := NormalizeTeamsUserID(detail.ID); id != "" {
+			names[id] = strings.TrimSpace(detail.FriendlyName)
+		}
+	}
+	for _, target := range parsed.Targets {
+		id := NormalizeTeamsUserID(target)
+		if id == "" {
+			continue
+		}
+		activity.Targets = append(activity.Targets, ThreadActivityMember{ID: id, DisplayName: names[id]})
+	}
+	switch activityType {
+	case ThreadActivityAddMember, ThreadActivityDeleteMember:
+		if len(activity.Targets) == 0 {
+			return nil
+		}
+	case ThreadActivityTopicUpdate:
+		if activity.Value == "" {
+			return nil
+		}
+	}
+	return activity
+}
+
+func parseEventTime(value string) time.Time {
+	ms, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil || ms <= 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms).UTC()
+}
@@ -0,0 +1,70 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func rawContent(t *testing.T, value string) json.RawMessage {
+	t.Helper()
+	raw, err := json.Marshal(value)
+	if err != nil {
+		t.Fatalf("marshal content: %v", err)
+	}
+	return raw
+}
+
+func TestParseThreadActivityAddMember(t *testing.T) {
+	content := rawContent(t, `<addmember><eventtime>1700000000123</eventtime><initiator>8:live:alice</initiator>`+
+		`<target>8:live:bob</target><target>8:live:carol</target>`+
+		`<detailedtargetinfo><id>8:live:bob</id><friendlyName>Bob</friendlyName></detailedtargetinfo></addmember>`)
+
+	activity := ParseThreadActivity("ThreadActivity/AddMember", content)
+	if activity == nil {
+		t.Fatalf("expected activity")
+	}
+	if activity.Type != ThreadActivityAddMember || activity.InitiatorID != "8:live:alice" {
+		t.Fatalf("unexpected activity: %#v", activity)
+	}
+	if activity.EventTime.UnixMilli() != 1700000000123 {
+		t.Fatalf("unexpected event time: %s", activity.EventTime)
+	}
+	if len(activity.Targets) != 2 || activity.Targets[0] != (ThreadActivityMember{ID: "8:live:bob", DisplayName: "Bob"}) || activity.Targets[1].ID != "8:live:carol" {
+		t.Fatalf("unexpected targets: %#v", activity.Targets)
+	}
+}
+
+func TestParseThreadActivityTopicUpdate(t *testing.T) {
+	content := rawContent(t, `<topicupdate><eventtime>1700000000123</eventtime><initiator>8:live:alice</initiator><value>Launch plans</value></topicupdate>`)
+
+	activity := ParseThreadActivity("ThreadActivity/TopicUpdate", content)
+	if activity == nil || activity.Type != ThreadActivityTopicUpdate || activity.Value != "Launch plans" {
+		t.Fatalf("unexpected activity: %#v", activity)
+	}
+}
+
+func TestParseThreadActivityIgnoresUnsupported(t *testing.T) {
+	if activity := ParseThreadActivity("ThreadActivity/HistoryDisclosedUpdate", rawContent(t, "<historydisclosedupdate/>")); activity != nil {
+		t.Fatalf("expected nil for unsupported activity, got %#v", activity)
+	}
+	if activity := ParseThreadActivity("ThreadActivity/DeleteMember", rawContent(t, "<deletemember></deletemember>")); activity != nil {
+		t.Fatalf("expected nil for activity without targets, got %#v", activity)
+	}
+	if activity := ParseThreadActivity("ThreadActivity/TopicUpdate", rawContent(t, "not xml")); activity != nil {
+		t.Fatalf("expected nil for malformed content, got %#v", activity)
+	}
+}
+
+func TestParseThreadActivityPictureUpdate(t *testing.T) {
+	content := rawContent(t, `<pictureupdate><eventtime>1700000000123</eventtime><initiator>8:live:alice</initiator>`+
+		`<value>URL@https://api.asm.skype.com/v1/objects/0-weu-d1-abc/views/avatar_fullsize</value></pictureupdate>`)
+	activity := ParseThreadActivity("ThreadActivity/PictureUpdate", content)
+	if activity == nil || activity.Type != ThreadActivityPictureUpdate || activity.Value != "https://api.asm.skype.com/v1/objects/0-weu-d1-abc/views/avatar_fullsize" {
+		t.Fatalf("unexpected activity: %#v", activity)
+	}
+
+	removed := ParseThreadActivity("ThreadActivity/PictureUpdate", rawContent(t, `<pictureupdate><initiator>8:live:alice</initiator><value></value></pictureupdate>`))
+	if removed == nil || removed.Value != "" {
+		t.Fatalf("expected picture removal, got %#v", removed)
+	}
+}
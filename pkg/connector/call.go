@@ -0,0 +1,130 @@
+package connector
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/rs/zerolog"
+
+	"maunium.net/go/mautrix/bridgev2"
+	"maunium.net/go/mautrix/bridgev2/database"
+	"maunium.net/go/mautrix/bridgev2/networkid"
+	"maunium.net/go/mautrix/bridgev2/simplevent"
+	"maunium.net/go/mautrix/event"
+
+	"go.mau.fi/mautrix-teams/internal/teams/model"
+	"go.mau.fi/mautrix-teams/pkg/teamsdb"
+	"go.mau.fi/mautrix-teams/pkg/teamsid"
+)
+
+// getCallNoticeQuery finds the notice bridged for the first event of a call by
+// the call ID kept in its metadata.
+const getCallNoticeQuery = `
+	SELECT id, sender_id
+	FROM message
+	WHERE bridge_id=$1 AND room_id=$2 AND room_receiver=$3 AND metadata->>'call_id'=$4
+	ORDER BY timestamp, part_id
+	LIMIT 1
+`
+
+func (c *TeamsClient) convertTeamsCallMessage(ctx context.Context, portal *bridgev2.Portal, intent bridgev2.MatrixAPI, msg model.RemoteMessage) (*bridgev2.ConvertedMessage, error) {
+	if msg.Call == nil {
+		return c.convertTeamsUnsupportedMessage(ctx, portal, intent, msg)
+	}
+	return &bridgev2.ConvertedMessage{
+		Parts: []*bridgev2.ConvertedMessagePart{{
+			Type:       event.EventMessage,
+			Content:    callNoticeContent(msg, msg.SenderName),
+			Extra:      perMessageExtra(msg),
+			DBMetadata: &teamsid.MessageMetadata{CallerName: msg.SenderName, CallID: msg.Call.CallID},
+		}},
+	}, nil
+}
+
+// convertTeamsCallEdit re-renders a call notice for a later event of the call.
+// The caller is taken from the notice, as the later event may come from anyone.
+func (c *TeamsClient) convertTeamsCallEdit(ctx context.Context, portal *bridgev2.Portal, intent bridgev2.MatrixAPI, existing []*database.Message, msg model.RemoteMessage) (*bridgev2.ConvertedEdit, error) {
+	if len(existing) == 0 || msg.Call == nil {
+		return nil, bridgev2.ErrIgnoringRemoteEvent
+	}
+	callerName := msg.SenderName
+	if meta, ok := existing[0].Metadata.(*teamsid.MessageMetadata); ok && meta != nil && meta.CallerName != "" {
+		callerName = meta.CallerName
+	}
+	part := &bridgev2.ConvertedMessagePart{
+		Type:    event.EventMessage,
+		Content: callNoticeContent(msg, callerName),
+		Extra:   perMessageExtra(msg),
+	}
+	return &bridgev2.ConvertedEdit{
+		ModifiedParts: []*bridgev2.ConvertedEditPart{part.ToEditPart(existing[0])},
+	}, nil
+}
+
+func callNoticeContent(msg model.RemoteMessage, callerName string) *event.MessageEventContent {
+	return &event.MessageEventContent{
+		MsgType: event.MsgNotice,
+		Body:    msg.Call.Notice(callerName),
+	}
+}
+
+// queueCallUpdate turns a later event of an already bridged call into an edit of
+// its notice, sent as whoever the notice was bridged from. It reports false if
+// the call hasn't been bridged yet, in which case the event should be bridged
+// as a new message. queuedCalls holds the notices of calls bridged earlier in
+// the same batch, which aren't in the database yet.
+func (c *TeamsClient) queueCallUpdate(ctx context.Context, th *teamsdb.ThreadState, msg model.RemoteMessage, queuedCalls map[string]*simplevent.Message[model.RemoteMessage]) bool {
+	var messageID networkid.MessageID
+	var sender bridgev2.EventSender
+	if notice, ok := queuedCalls[msg.Call.CallID]; ok {
+		messageID, sender = notice.ID, notice.Sender
+	} else {
+		var senderID networkid.UserID
+		var err error
+		messageID, senderID, err = c.getCallNotice(ctx, th, msg.Call.CallID)
+		if err != nil {
+			zerolog.Ctx(ctx).Warn().Err(err).Str("call_id", msg.Call.CallID).Msg("Failed to look up call notice")
+			return false
+		} else if messageID == "" {
+			return false
+		}
+		sender = c.teamsEventSender(string(senderID))
+	}
+	c.queueRemoteEvent(&simplevent.Message[model.RemoteMessage]{
+		EventMeta: simplevent.EventMeta{
+			Type:      bridgev2.RemoteEventEdit,
+			PortalKey: c.portalKey(th.ThreadID),
+			Sender:    sender,
+			Timestamp: msg.Timestamp,
+		},
+		Data:            msg,
+		ID:              messageID,
+		TargetMessage:   messageID,
+		ConvertEditFunc: c.convertTeamsCallEdit,
+	})
+	return true
+}
+
+func (c *TeamsClient) getCallNotice(ctx context.Context, th *teamsdb.ThreadState, callID string) (networkid.MessageID, networkid.UserID, error) {
+	if c.Main == nil || c.Main.Bridge == nil || c.Main.Bridge.DB == nil {
+		return "", "", nil
+	}
+	portalKey := c.portalKey(th.ThreadID)
+	var messageID networkid.MessageID
+	var senderID networkid.UserID
+	err := c.Main.Bridge.DB.QueryRow(
+		ctx,
+		getCallNoticeQuery,
+		c.Main.Bridge.DB.BridgeID,
+		portalKey.ID,
+		portalKey.Receiver,
+		callID,
+	).Scan(&messageID, &senderID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", "", nil
+	} else if err != nil {
+		return "", "", err
+	}
+	return messageID, senderID, nil
+}
@@ -38,6 +38,12 @@ type TeamsClient struct {
 	reactionSeenMu sync.Mutex
 	reactionSeen   map[string]struct{}
 
+	editSeenMu sync.Mutex
+	editSeen   map[string]seenEdit
+
+	deleteSeenMu sync.Mutex
+	deleteSeen   map[string]time.Time
+
 	receiptPollMu sync.Mutex
 	receiptPoll   map[string]time.Time
 	unreadMu      sync.Mutex
@@ -45,13 +51,19 @@ type TeamsClient struct {
 	unreadSent    map[string]bool
 	selfMessageMu sync.Mutex
 	selfMessages  map[string]time.Time
+	selfEdits     map[string]time.Time
+
+	// remoteEventSink replaces Login.QueueRemoteEvent in tests.
+	remoteEventSink func(bridgev2.RemoteEvent)
 }
 
 var (
 	_ bridgev2.NetworkAPI                    = (*TeamsClient)(nil)
 	_ bridgev2.BackgroundSyncingNetworkAPI   = (*TeamsClient)(nil)
+	_ bridgev2.EditHandlingNetworkAPI        = (*TeamsClient)(nil)
 	_ bridgev2.ReactionHandlingNetworkAPI    = (*TeamsClient)(nil)
 	_ bridgev2.ReadReceiptHandlingNetworkAPI = (*TeamsClient)(nil)
+	_ bridgev2.RedactionHandlingNetworkAPI   = (*TeamsClient)(nil)
 	_ bridgev2.TypingHandlingNetworkAPI      = (*TeamsClient)(nil)
 )
 
@@ -186,14 +198,19 @@ func (c *TeamsClient) GetCapabilities(ctx context.Context, portal *bridgev2.Port
 	}
 	return &event.RoomFeatures{
 		// Bump when capabilities change so Beeper refreshes cached feature info.
-		ID: "fi.mau.teams.capabilities.2026_02_12_3",
+		ID: "fi.mau.teams.capabilities.2026_10_16_5",
 		File: event.FileFeatureMap{
-			event.MsgFile:  fileFeatures,
-			event.MsgImage: fileFeatures,
-			event.MsgVideo: fileFeatures,
-			event.MsgAudio: fileFeatures,
+			event.MsgFile:     fileFeatures,
+			event.MsgImage:    fileFeatures,
+			event.MsgVideo:    fileFeatures,
+			event.MsgAudio:    fileFeatures,
+			event.CapMsgVoice: fileFeatures,
 		},
+		Edit:                   event.CapLevelFullySupported,
+		Delete:                 event.CapLevelFullySupported,
+		Reply:                  event.CapLevelFullySupported,
 		Reaction:               event.CapLevelFullySupported,
+		LocationMessage:        event.CapLevelFullySupported,
 		TypingNotifications:    true,
 		ReadReceipts:           true,
 		PerMessageProfileRelay: true,
@@ -225,6 +242,15 @@ func (c *TeamsClient) getConsumerHTTP() *http.Client {
 	return c.consumerHTTP
 }
 
+// queueRemoteEvent hands a Teams event to bridgev2.
+func (c *TeamsClient) queueRemoteEvent(evt bridgev2.RemoteEvent) {
+	if c.remoteEventSink != nil {
+		c.remoteEventSink(evt)
+		return
+	}
+	c.Login.QueueRemoteEvent(evt)
+}
+
 func (c *TeamsClient) newConsumer() *consumerclient.Client {
 	if c == nil {
 		return nil
@@ -277,15 +303,50 @@ func (c *TeamsClient) consumeSelfMessage(clientMessageID string) bool {
 	return exists
 }
 
-func (c *TeamsClient) cleanupSelfMessagesLocked(now time.Time) {
-	if c.selfMessages == nil {
+func (c *TeamsClient) recordSelfEdit(teamsMessageID string) {
+	teamsMessageID = strings.TrimSpace(teamsMessageID)
+	if teamsMessageID == "" {
 		return
 	}
+	c.selfMessageMu.Lock()
+	defer c.selfMessageMu.Unlock()
+	now := time.Now().UTC()
+	if c.selfEdits == nil {
+		c.selfEdits = make(map[string]time.Time)
+	}
+	c.cleanupSelfMessagesLocked(now)
+	c.selfEdits[teamsMessageID] = now
+}
+
+func (c *TeamsClient) consumeSelfEdit(teamsMessageID string) bool {
+	teamsMessageID = strings.TrimSpace(teamsMessageID)
+	if teamsMessageID == "" {
+		return false
+	}
+	c.selfMessageMu.Lock()
+	defer c.selfMessageMu.Unlock()
+	if c.selfEdits == nil {
+		return false
+	}
+	c.cleanupSelfMessagesLocked(time.Now().UTC())
+	_, exists := c.selfEdits[teamsMessageID]
+	if exists {
+		delete(c.selfEdits, teamsMessageID)
+	}
+	return exists
+}
+
+func (c *TeamsClient) cleanupSelfMessagesLocked(now time.Time) {
 	for id, ts := range c.selfMessages {
 		if now.Sub(ts) > selfMessageTTL {
 			delete(c.selfMessages, id)
 		}
 	}
+	for id, ts := range c.selfEdits {
+		if now.Sub(ts) > selfMessageTTL {
+			delete(c.selfEdits, id)
+		}
+	}
 }
 
 func ptrString(v string) *string { return &v }
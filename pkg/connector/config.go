@@ -13,10 +13,16 @@ type TeamsConfig struct {
 	// OAuth client ID used by the Teams web app. This must match the ID used in MSAL localStorage keys.
 	// If unset, the connector uses the default client ID from internal/teams/auth.
 	ClientID string `yaml:"client_id"`
+	// Also delete the OneDrive file uploaded for an attachment when the Matrix message is redacted.
+	DeleteAttachmentFiles bool `yaml:"delete_attachment_files"`
+	// Render Markdown in Matrix messages that only have a plain body as Teams formatting.
+	RenderMarkdown bool `yaml:"render_markdown"`
 }
 
 func upgradeConfig(helper up.Helper) {
 	helper.Copy(up.Str, "client_id")
+	helper.Copy(up.Bool, "delete_attachment_files")
+	helper.Copy(up.Bool, "render_markdown")
 }
 
 func (t *TeamsConnector) GetConfig() (string, any, up.Upgrader) {
@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"maunium.net/go/mautrix/bridgev2"
+	"maunium.net/go/mautrix/bridgev2/database"
 	"maunium.net/go/mautrix/bridgev2/networkid"
 	"maunium.net/go/mautrix/event"
 	"maunium.net/go/mautrix/id"
@@ -17,9 +18,41 @@ import (
 	internalbridge "go.mau.fi/mautrix-teams/internal/bridge"
 	"go.mau.fi/mautrix-teams/internal/teams/graph"
 	"go.mau.fi/mautrix-teams/internal/teams/model"
+	"go.mau.fi/mautrix-teams/pkg/teamsid"
 )
 
 func (c *TeamsClient) convertTeamsMessage(ctx context.Context, portal *bridgev2.Portal, intent bridgev2.MatrixAPI, msg model.RemoteMessage) (*bridgev2.ConvertedMessage, error) {
+	if msg.Poll != nil && !msg.Poll.Closed {
+		return convertTeamsPoll(msg), nil
+	}
+	msg, mentions := c.applyMentions(msg)
+	msg, replyTo := c.applyReplyTo(ctx, portal, msg)
+	msg = c.applyForward(ctx, msg)
+	msg = applyImportance(msg)
+	msg = applyCards(msg)
+	converted, err := c.convertTeamsMessageContent(ctx, portal, intent, msg)
+	if err != nil || converted == nil {
+		return converted, err
+	}
+	converted.ReplyTo = replyTo
+	setTextPartMentions(converted.Parts, mentions)
+	if msg.IsEdited() {
+		// Remember the revision so re-delivered copies of the same edit are ignored.
+		for _, part := range converted.Parts {
+			part.DBMetadata = &teamsid.MessageMetadata{EditTime: msg.EditTime.UnixMilli()}
+		}
+	}
+	if msg.Subject != "" || msg.Importance != model.ImportanceNormal {
+		for _, part := range converted.Parts {
+			meta := partMetadata(part)
+			meta.Subject, meta.Importance = msg.Subject, string(msg.Importance)
+		}
+	}
+	setTextPartLinkPreviews(converted.Parts, c.convertLinkPreviews(ctx, portal, intent, msg.LinkPreviews, nil))
+	return converted, nil
+}
+
+func (c *TeamsClient) convertTeamsMessageContent(ctx context.Context, portal *bridgev2.Portal, intent bridgev2.MatrixAPI, msg model.RemoteMessage) (*bridgev2.ConvertedMessage, error) {
 	attachments, _ := model.ParseAttachments(msg.PropertiesFiles)
 	// If no attachments have DriveItemIDs (i.e. no Graph download ID), preserve legacy behavior.
 	// This keeps the conversion robust for older payload variants.
@@ -30,7 +63,8 @@ func (c *TeamsClient) convertTeamsMessage(ctx context.Context, portal *bridgev2.
 			break
 		}
 	}
-	if !hasDriveItemID || intent == nil || c == nil {
+	hasInlineImages := len(msg.InlineImages) > 0
+	if (!hasDriveItemID && !hasInlineImages) || intent == nil || c == nil {
 		return convertTeamsMessageLegacy(msg), nil
 	}
 
@@ -40,13 +74,24 @@ func (c *TeamsClient) convertTeamsMessage(ctx context.Context, portal *bridgev2.
 	if portal != nil {
 		roomID = portal.MXID
 	}
-	mediaParts, fallback := c.reuploadInboundAttachments(ctx, roomID, intent, attachments, extra)
+	var mediaParts []*bridgev2.ConvertedMessagePart
+	fallback := attachments
+	if hasDriveItemID {
+		mediaParts, fallback = c.reuploadInboundAttachments(ctx, roomID, intent, attachments, extra)
+	}
+	var failedImages []model.InlineImage
+	if hasInlineImages {
+		var imageParts []*bridgev2.ConvertedMessagePart
+		imageParts, failedImages = c.reuploadInlineImages(ctx, roomID, intent, msg.InlineImages, extra)
+		mediaParts = append(mediaParts, imageParts...)
+	}
 	parts := make([]*bridgev2.ConvertedMessagePart, 0, len(mediaParts)+1)
 	parts = append(parts, mediaParts...)
 
 	// Caption: always preserve Teams message body (and include any GIFs and fallback attachment lines)
 	// as a separate m.text message after all attachment parts.
 	captionRendered := renderInboundMessageWithGIFs(msg.Body, msg.FormattedBody, fallback, msg.GIFs)
+	captionRendered = renderFailedInlineImages(captionRendered, failedImages)
 	if captionPart := buildCaptionPart(networkid.PartID("caption"), captionRendered, extra); captionPart != nil {
 		parts = append(parts, captionPart)
 	}
@@ -65,6 +110,96 @@ func (c *TeamsClient) convertTeamsMessage(ctx context.Context, portal *bridgev2.
 	return &bridgev2.ConvertedMessage{Parts: parts}, nil
 }
 
+func (c *TeamsClient) convertTeamsEdit(ctx context.Context, portal *bridgev2.Portal, intent bridgev2.MatrixAPI, existing []*database.Message, msg model.RemoteMessage) (*bridgev2.ConvertedEdit, error) {
+	if len(existing) == 0 || !msg.IsEdited() {
+		return nil, bridgev2.ErrIgnoringRemoteEvent
+	}
+	editTS := msg.EditTime.UnixMilli()
+	existingByPart := make(map[networkid.PartID]*database.Message, len(existing))
+	for _, part := range existing {
+		if meta, ok := part.Metadata.(*teamsid.MessageMetadata); ok && meta != nil &&
+			(meta.EditTime >= editTS || (meta.EditBody != "" && meta.EditBody == strings.TrimSpace(msg.Body))) {
+			return nil, bridgev2.ErrIgnoringRemoteEvent
+		}
+		existingByPart[part.PartID] = part
+	}
+	if start, ok := existingByPart[pollPartID]; ok {
+		return convertTeamsPollEdit(msg, start, existingByPart)
+	}
+	if msg.Kind == model.MessageKindMedia {
+		// As in convertTeamsMediaMessage, the URIObject text isn't part of the message.
+		msg.Body = ""
+		msg.FormattedBody = ""
+	}
+
+	msg, mentions := c.applyMentions(msg)
+	// Reply relations can't change on edit, only the fallback quote is refreshed.
+	msg, _ = c.applyReplyTo(ctx, portal, msg)
+	msg = c.applyForward(ctx, msg)
+	msg = applyImportance(msg)
+	msg = applyCards(msg)
+
+	// Edits only rewrite the text. Attachments that were re-uploaded as media parts
+	// are left alone; the rest keep their fallback lines in the text part.
+	attachments, _ := model.ParseAttachments(msg.PropertiesFiles)
+	var fallback []model.TeamsAttachment
+	for i, att := range attachments {
+		if _, ok := existingByPart[networkid.PartID(fmt.Sprintf("att_%d", i))]; !ok {
+			fallback = append(fallback, att)
+		}
+	}
+	rendered := renderInboundMessageWithGIFs(msg.Body, msg.FormattedBody, fallback, msg.GIFs)
+
+	target, ok := existingByPart[networkid.PartID("caption")]
+	if !ok {
+		target, ok = existingByPart[""]
+	}
+	partID := networkid.PartID("caption")
+	if ok {
+		partID = target.PartID
+	}
+	part := buildCaptionPart(partID, rendered, perMessageExtra(msg))
+	if part == nil {
+		return nil, bridgev2.ErrIgnoringRemoteEvent
+	}
+	part.DBMetadata = &teamsid.MessageMetadata{EditTime: editTS, Subject: msg.Subject, Importance: string(msg.Importance)}
+	setTextPartMentions([]*bridgev2.ConvertedMessagePart{part}, mentions)
+	var previous []*event.BeeperLinkPreview
+	if ok {
+		if meta, _ := target.Metadata.(*teamsid.MessageMetadata); meta != nil {
+			previous = meta.LinkPreviews
+		}
+	}
+	setTextPartLinkPreviews([]*bridgev2.ConvertedMessagePart{part}, c.convertLinkPreviews(ctx, portal, intent, msg.LinkPreviews, previous))
+	if !ok {
+		return &bridgev2.ConvertedEdit{
+			AddedParts: &bridgev2.ConvertedMessage{Parts: []*bridgev2.ConvertedMessagePart{part}},
+		}, nil
+	}
+	return &bridgev2.ConvertedEdit{
+		ModifiedParts: []*bridgev2.ConvertedEditPart{part.ToEditPart(target)},
+	}, nil
+}
+
+// applyCards appends the rendered cards to the message text, so they end up in
+// the caption like the rest of the body. Polls that can't be bridged as Matrix
+// polls are rendered as their question and options.
+func applyCards(msg model.RemoteMessage) model.RemoteMessage {
+	if len(msg.Cards) == 0 {
+		return msg
+	}
+	var text, formatted string
+	if msg.Poll != nil {
+		text, formatted = model.RenderPoll(msg.Poll)
+	} else {
+		text, formatted = model.RenderCards(msg.Cards)
+	}
+	if strings.TrimSpace(text) == "" && formatted == "" {
+		return msg
+	}
+	return appendHTMLBlock(msg, text, formatted)
+}
+
 func convertTeamsMessageLegacy(msg model.RemoteMessage) *bridgev2.ConvertedMessage {
 	attachments, _ := model.ParseAttachments(msg.PropertiesFiles)
 	rendered := renderInboundMessageWithGIFs(msg.Body, msg.FormattedBody, attachments, msg.GIFs)
@@ -110,6 +245,15 @@ func perMessageExtra(msg model.RemoteMessage) map[string]any {
 			}
 		}
 	}
+	if msg.ForwardedFrom != nil {
+		extra[forwardedExtraKey] = forwardedExtra(msg.ForwardedFrom)
+	}
+	if msg.Importance != model.ImportanceNormal {
+		extra[importanceExtraKey] = string(msg.Importance)
+	}
+	if subject := strings.TrimSpace(msg.Subject); subject != "" {
+		extra[subjectExtraKey] = subject
+	}
 	if len(extra) == 0 {
 		return nil
 	}
@@ -437,3 +581,45 @@ func plainTextToHTML(text string) string {
 	escaped = strings.ReplaceAll(escaped, "\n", "<br>")
 	return escaped
 }
+
+// partMetadata returns the metadata a converted part will be stored with,
+// creating it if needed.
+func partMetadata(part *bridgev2.ConvertedMessagePart) *teamsid.MessageMetadata {
+	meta, _ := part.DBMetadata.(*teamsid.MessageMetadata)
+	if meta == nil {
+		meta = &teamsid.MessageMetadata{}
+		part.DBMetadata = meta
+	}
+	return meta
+}
+
+// messageHTML is the formatted body of a message, falling back to its escaped
+// plain text.
+func messageHTML(msg model.RemoteMessage) string {
+	formatted := strings.TrimSpace(msg.FormattedBody)
+	if formatted == "" && strings.TrimSpace(msg.Body) != "" {
+		formatted = plainTextToHTML(strings.TrimSpace(msg.Body))
+	}
+	return formatted
+}
+
+// prependHTMLHeader puts a header line above the message text, given as plain
+// text and as inline HTML.
+func prependHTMLHeader(msg model.RemoteMessage, text, headerHTML string) model.RemoteMessage {
+	formatted := messageHTML(msg)
+	msg.Body = strings.TrimSpace(text + "\n" + strings.TrimSpace(msg.Body))
+	msg.FormattedBody = "<p>" + headerHTML + "</p>" + formatted
+	return msg
+}
+
+// appendHTMLBlock puts a block below the message text, given as plain text and
+// as HTML.
+func appendHTMLBlock(msg model.RemoteMessage, text, blockHTML string) model.RemoteMessage {
+	formatted := messageHTML(msg)
+	if body := strings.TrimSpace(msg.Body); body != "" {
+		text = body + "\n\n" + text
+	}
+	msg.Body = text
+	msg.FormattedBody = formatted + blockHTML
+	return msg
+}
@@ -2,13 +2,20 @@ package connector
 
 import (
 	"context"
+	"errors"
+	"io"
+	"net/http"
 	"strings"
 	"testing"
+	"time"
 
+	"maunium.net/go/mautrix/bridgev2"
+	"maunium.net/go/mautrix/bridgev2/database"
 	"maunium.net/go/mautrix/event"
 	"maunium.net/go/mautrix/id"
 
 	"go.mau.fi/mautrix-teams/internal/teams/model"
+	"go.mau.fi/mautrix-teams/pkg/teamsid"
 )
 
 func TestRenderInboundMessageTextOnly(t *testing.T) {
@@ -101,3 +108,170 @@ func TestBuildMediaContentDoesNotClobberEncryptedFileURL(t *testing.T) {
 		t.Fatalf("expected empty url field when encrypted file is present, got %q", got.URL)
 	}
 }
+
+func TestConvertTeamsEditReplacesTextPart(t *testing.T) {
+	editTime := time.UnixMilli(1700000000123).UTC()
+	existing := []*database.Message{{ID: "m1", Metadata: &teamsid.MessageMetadata{}}}
+	msg := model.RemoteMessage{MessageID: "m1", Body: "fixed", EditTime: editTime}
+
+	converted, err := (&TeamsClient{}).convertTeamsEdit(context.Background(), nil, nil, existing, msg)
+	if err != nil {
+		t.Fatalf("convertTeamsEdit failed: %v", err)
+	}
+	if len(converted.ModifiedParts) != 1 {
+		t.Fatalf("expected one modified part, got %d", len(converted.ModifiedParts))
+	}
+	part := converted.ModifiedParts[0]
+	if part.Part != existing[0] || part.Content.Body != "fixed" {
+		t.Fatalf("unexpected modified part: %#v", part)
+	}
+	meta, ok := existing[0].Metadata.(*teamsid.MessageMetadata)
+	if !ok || meta.EditTime != editTime.UnixMilli() {
+		t.Fatalf("expected edit time metadata, got %#v", existing[0].Metadata)
+	}
+}
+
+func TestConvertTeamsEditIgnoresSeenRevision(t *testing.T) {
+	editTime := time.UnixMilli(1700000000123).UTC()
+	existing := []*database.Message{{ID: "m1", Metadata: &teamsid.MessageMetadata{EditTime: editTime.UnixMilli()}}}
+	msg := model.RemoteMessage{MessageID: "m1", Body: "fixed", EditTime: editTime}
+
+	_, err := (&TeamsClient{}).convertTeamsEdit(context.Background(), nil, nil, existing, msg)
+	if !errors.Is(err, bridgev2.ErrIgnoringRemoteEvent) {
+		t.Fatalf("expected ErrIgnoringRemoteEvent, got %v", err)
+	}
+}
+
+func TestConvertTeamsEditIgnoresMatrixEditEcho(t *testing.T) {
+	existing := []*database.Message{{ID: "m1", Metadata: &teamsid.MessageMetadata{EditTime: 1, EditBody: "fixed"}}}
+	echo := model.RemoteMessage{MessageID: "m1", Body: "fixed", EditTime: time.UnixMilli(1700000000123).UTC()}
+	if _, err := (&TeamsClient{}).convertTeamsEdit(context.Background(), nil, nil, existing, echo); !errors.Is(err, bridgev2.ErrIgnoringRemoteEvent) {
+		t.Fatalf("expected the echo to be ignored, got %v", err)
+	}
+
+	later := model.RemoteMessage{MessageID: "m1", Body: "fixed again", EditTime: time.UnixMilli(1700000000456).UTC()}
+	converted, err := (&TeamsClient{}).convertTeamsEdit(context.Background(), nil, nil, existing, later)
+	if err != nil || converted.ModifiedParts[0].Content.Body != "fixed again" {
+		t.Fatalf("expected a later edit to be bridged, got %#v %v", converted, err)
+	}
+	if meta := existing[0].Metadata.(*teamsid.MessageMetadata); meta.EditBody != "" {
+		t.Fatalf("expected the echo text to be cleared, got %q", meta.EditBody)
+	}
+}
+
+func TestConvertTeamsMessageStoresEditTime(t *testing.T) {
+	msg := model.RemoteMessage{Body: "hello", EditTime: time.UnixMilli(1700000000123).UTC()}
+	converted, err := (&TeamsClient{}).convertTeamsMessage(context.Background(), nil, nil, msg)
+	if err != nil {
+		t.Fatalf("convertTeamsMessage failed: %v", err)
+	}
+	meta, ok := converted.Parts[0].DBMetadata.(*teamsid.MessageMetadata)
+	if !ok || meta.EditTime != 1700000000123 {
+		t.Fatalf("unexpected part metadata: %#v", converted.Parts[0].DBMetadata)
+	}
+}
+
+func TestConvertTeamsMessageLinkPreviews(t *testing.T) {
+	msg := model.RemoteMessage{
+		Body: "see https://example.com/a",
+		LinkPreviews: []model.LinkPreview{{
+			URL:         "https://example.com/a",
+			Title:       "Example A",
+			Description: "About A",
+		}},
+	}
+	converted, err := (&TeamsClient{}).convertTeamsMessage(context.Background(), nil, nil, msg)
+	if err != nil {
+		t.Fatalf("convert failed: %v", err)
+	}
+	previews := converted.Parts[0].Content.BeeperLinkPreviews
+	if len(previews) != 1 {
+		t.Fatalf("expected one link preview, got %#v", previews)
+	}
+	if previews[0].MatchedURL != "https://example.com/a" || previews[0].Title != "Example A" || previews[0].Description != "About A" || previews[0].ImageURL != "" {
+		t.Fatalf("unexpected link preview: %#v", previews[0])
+	}
+}
+
+type statusRoundTripper struct {
+	status   int
+	requests *int
+}
+
+func (rt statusRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	*rt.requests++
+	return &http.Response{
+		StatusCode: rt.status,
+		Body:       io.NopCloser(strings.NewReader("{}")),
+		Request:    req,
+	}, nil
+}
+
+// uploadlessIntent panics if anything tries to upload media through it.
+type uploadlessIntent struct {
+	bridgev2.MatrixAPI
+}
+
+func TestConvertLinkPreviewsSkipsNonAMSImage(t *testing.T) {
+	requests := 0
+	client := &TeamsClient{
+		Meta:         &teamsid.UserLoginMetadata{SkypeToken: "token123"},
+		consumerHTTP: &http.Client{Transport: statusRoundTripper{status: http.StatusOK, requests: &requests}},
+	}
+	for _, imageURL := range []string{
+		"https://cdn.example.com/a.png",
+		"https://169.254.169.254/latest/meta-data",
+		"https://asm.skype.com.evil.example/a.png",
+		"http://urlp.asm.skype.com/v1/url/content?url=x",
+	} {
+		previews := client.convertLinkPreviews(context.Background(), nil, uploadlessIntent{}, []model.LinkPreview{{
+			URL:      "https://example.com/a",
+			Title:    "Example A",
+			ImageURL: imageURL,
+		}}, nil)
+		if len(previews) != 1 || previews[0].Title != "Example A" || previews[0].ImageURL != "" {
+			t.Fatalf("expected preview without image for %s, got %#v", imageURL, previews)
+		}
+	}
+	if requests != 0 {
+		t.Fatalf("expected non-AMS preview images not to be fetched, got %d requests", requests)
+	}
+}
+
+func TestConvertTeamsEditReusesLinkPreviewImage(t *testing.T) {
+	requests := 0
+	client := &TeamsClient{
+		Meta:         &teamsid.UserLoginMetadata{SkypeToken: "token123"},
+		consumerHTTP: &http.Client{Transport: statusRoundTripper{status: http.StatusOK, requests: &requests}},
+	}
+	bridged := &event.BeeperLinkPreview{
+		MatchedURL:  "https://example.com/a",
+		LinkPreview: event.LinkPreview{ImageURL: "mxc://example.org/thumb", ImageType: "image/png"},
+	}
+	existing := []*database.Message{{ID: "m1", Metadata: &teamsid.MessageMetadata{LinkPreviews: []*event.BeeperLinkPreview{bridged}}}}
+	msg := model.RemoteMessage{
+		MessageID: "m1",
+		Body:      "see https://example.com/a again",
+		EditTime:  time.UnixMilli(1700000000123).UTC(),
+		LinkPreviews: []model.LinkPreview{{
+			URL:      "https://example.com/a",
+			Title:    "Example A",
+			ImageURL: "https://urlp.asm.skype.com/v1/url/content?url=x",
+		}},
+	}
+	converted, err := client.convertTeamsEdit(context.Background(), nil, uploadlessIntent{}, existing, msg)
+	if err != nil {
+		t.Fatalf("convertTeamsEdit failed: %v", err)
+	}
+	previews := converted.ModifiedParts[0].Content.BeeperLinkPreviews
+	if len(previews) != 1 || previews[0].Title != "Example A" || previews[0].ImageURL != "mxc://example.org/thumb" || previews[0].ImageType != "image/png" {
+		t.Fatalf("expected the bridged thumbnail to be reused, got %#v", previews)
+	}
+	if requests != 0 {
+		t.Fatalf("expected the thumbnail not to be downloaded again, got %d requests", requests)
+	}
+	meta, ok := existing[0].Metadata.(*teamsid.MessageMetadata)
+	if !ok || len(meta.LinkPreviews) != 1 || meta.LinkPreviews[0].ImageURL != "mxc://example.org/thumb" {
+		t.Fatalf("expected the previews to stay in the metadata, got %#v", existing[0].Metadata)
+	}
+}
@@ -0,0 +1,83 @@
+package connector
+
+import (
+	"context"
+	"html"
+	"time"
+
+	"maunium.net/go/mautrix/event"
+
+	"go.mau.fi/mautrix-teams/internal/teams/model"
+)
+
+// forwardedExtraKey marks forwarded messages in both directions. Inbound events
+// carry the original sender under it; Matrix events that set it are sent to
+// Teams as forwards.
+const forwardedExtraKey = "fi.mau.teams.forwarded"
+
+// applyForward puts a "Forwarded from X" header above the forwarded content.
+// The cached profile name is preferred over the one frozen into the forward block.
+func (c *TeamsClient) applyForward(ctx context.Context, msg model.RemoteMessage) model.RemoteMessage {
+	forward := msg.ForwardedFrom
+	if forward == nil {
+		return msg
+	}
+	name := ""
+	if forward.SenderID != "" {
+		name = c.cachedDisplayName(ctx, forward.SenderID)
+	}
+	if name == "" {
+		name = forward.SenderName
+	}
+	if name == "" {
+		name = forward.SenderID
+	}
+
+	header := "Forwarded message"
+	headerHTML := "<em>Forwarded message</em>"
+	if name != "" {
+		header = "Forwarded from " + name
+		headerHTML = "<em>Forwarded from <strong>" + html.EscapeString(name) + "</strong></em>"
+	}
+	return prependHTMLHeader(msg, header, headerHTML)
+}
+
+func forwardedExtra(forward *model.MessageForward) map[string]any {
+	out := make(map[string]any, 4)
+	if forward.MessageID != "" {
+		out["message_id"] = forward.MessageID
+	}
+	if forward.SenderID != "" {
+		out["sender_id"] = forward.SenderID
+	}
+	if forward.SenderName != "" {
+		out["sender_name"] = forward.SenderName
+	}
+	if !forward.Timestamp.IsZero() {
+		out["timestamp"] = forward.Timestamp.UnixMilli()
+	}
+	return out
+}
+
+// matrixForwardOrigin reads the forward flag of an outgoing Matrix event. The
+// flag may be a bare true or an object in the same shape the bridge emits.
+func matrixForwardOrigin(evt *event.Event) (model.MessageForward, bool) {
+	if evt == nil || evt.Content.Raw == nil {
+		return model.MessageForward{}, false
+	}
+	switch flag := evt.Content.Raw[forwardedExtraKey].(type) {
+	case bool:
+		return model.MessageForward{}, flag
+	case map[string]any:
+		origin := model.MessageForward{}
+		origin.MessageID, _ = flag["message_id"].(string)
+		origin.SenderID, _ = flag["sender_id"].(string)
+		origin.SenderName, _ = flag["sender_name"].(string)
+		if ms, ok := flag["timestamp"].(float64); ok && ms > 0 {
+			origin.Timestamp = time.UnixMilli(int64(ms))
+		}
+		return origin, true
+	default:
+		return model.MessageForward{}, false
+	}
+}
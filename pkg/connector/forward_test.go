@@ -0,0 +1,53 @@
+package connector
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"maunium.net/go/mautrix/event"
+
+	"go.mau.fi/mautrix-teams/internal/teams/model"
+)
+
+func TestConvertTeamsMessageForwarded(t *testing.T) {
+	msg := model.RemoteMessage{
+		Body:          "original text",
+		FormattedBody: "<p>original <b>text</b></p>",
+		ForwardedFrom: &model.MessageForward{
+			SenderID:   "8:live:alice",
+			SenderName: "Alice <A>",
+			Timestamp:  time.UnixMilli(1700000000000),
+		},
+	}
+	converted, err := (&TeamsClient{}).convertTeamsMessage(context.Background(), nil, nil, msg)
+	if err != nil {
+		t.Fatalf("convert failed: %v", err)
+	}
+	part := converted.Parts[0]
+	if part.Content.Body != "Forwarded from Alice <A>\noriginal text" {
+		t.Fatalf("unexpected body: %q", part.Content.Body)
+	}
+	if part.Content.FormattedBody != "<p><em>Forwarded from <strong>Alice &lt;A&gt;</strong></em></p><p>original <b>text</b></p>" {
+		t.Fatalf("unexpected formatted body: %q", part.Content.FormattedBody)
+	}
+	forwarded, ok := part.Extra[forwardedExtraKey].(map[string]any)
+	if !ok || forwarded["sender_id"] != "8:live:alice" || forwarded["timestamp"] != int64(1700000000000) {
+		t.Fatalf("unexpected forwarded extra: %#v", part.Extra)
+	}
+}
+
+func TestMatrixForwardOrigin(t *testing.T) {
+	if _, ok := matrixForwardOrigin(&event.Event{Content: event.Content{Raw: map[string]any{"body": "x"}}}); ok {
+		t.Fatal("expected plain messages not to be forwards")
+	}
+	if _, ok := matrixForwardOrigin(&event.Event{Content: event.Content{Raw: map[string]any{forwardedExtraKey: true}}}); !ok {
+		t.Fatal("expected bare flag to mark a forward")
+	}
+	origin, ok := matrixForwardOrigin(&event.Event{Content: event.Content{Raw: map[string]any{
+		forwardedExtraKey: map[string]any{"sender_id": "8:live:alice", "sender_name": "Alice", "timestamp": float64(1700000000000)},
+	}}})
+	if !ok || origin.SenderID != "8:live:alice" || origin.SenderName != "Alice" || origin.Timestamp.UnixMilli() != 1700000000000 {
+		t.Fatalf("unexpected origin: %#v", origin)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/rs/zerolog"
 	"maunium.net/go/mautrix/bridgev2"
 	"maunium.net/go/mautrix/bridgev2/database"
 	"maunium.net/go/mautrix/bridgev2/networkid"
@@ -14,6 +15,9 @@ import (
 
 	internalbridge "go.mau.fi/mautrix-teams/internal/bridge"
 	consumerclient "go.mau.fi/mautrix-teams/internal/teams/client"
+	"go.mau.fi/mautrix-teams/internal/teams/graph"
+	"go.mau.fi/mautrix-teams/internal/teams/model"
+	"go.mau.fi/mautrix-teams/pkg/teamsid"
 )
 
 func (c *TeamsClient) HandleMatrixMessage(ctx context.Context, msg *bridgev2.MatrixMessage) (*bridgev2.MatrixMessageResponse, error) {
@@ -46,42 +50,44 @@ func (c *TeamsClient) HandleMatrixMessage(ctx context.Context, msg *bridgev2.Mat
 	}
 	msg.AddPendingToSave(pendingMessage, networkid.TransactionID(clientMessageID), nil)
 
+	send := func(ctx context.Context, threadID, filename string, content []byte, caption string) error {
+		sent, err := c.sendAttachmentMessageWithClientMessageID(ctx, threadID, filename, content, caption, clientMessageID)
+		if err != nil {
+			return err
+		}
+		pendingMessage.Metadata = &teamsid.MessageMetadata{DriveItemID: sent.DriveItemID}
+		return nil
+	}
+	download := func(ctx context.Context, mxcURL string, file *event.EncryptedFileInfo) ([]byte, error) {
+		return c.downloadMatrixMedia(ctx, mxcURL, file)
+	}
+
 	var err error
 	switch msg.Content.MsgType {
 	case event.MsgText:
-		_, err = consumer.SendMessageWithID(ctx, threadID, msg.Content.Body, c.Meta.TeamsUserID, clientMessageID)
+		body := c.outboundMessageBody(ctx, msg.Content)
+		body.Subject, body.Importance = matrixImportance(msg.Event)
+		if body.Subject != "" || body.Importance != model.ImportanceNormal {
+			pendingMessage.Metadata = &teamsid.MessageMetadata{Subject: body.Subject, Importance: string(body.Importance)}
+		}
+		if origin, ok := matrixForwardOrigin(msg.Event); ok {
+			body = consumerclient.ForwardedBody(body, origin)
+		}
+		if quote, ok := c.buildReplyQuote(ctx, msg.Portal, msg.ReplyTo); ok {
+			_, err = consumer.SendReplyWithID(ctx, threadID, body, quote, c.Meta.TeamsUserID, clientMessageID)
+		} else {
+			_, err = consumer.SendMessageWithID(ctx, threadID, body, c.Meta.TeamsUserID, clientMessageID)
+		}
 	case event.MsgImage:
 		title, gifURL, ok := extractOutboundGIF(msg.Content)
 		if !ok {
-			// Not a GIF: treat it like a normal attachment (e.g. PNG/JPEG).
-			send := func(ctx context.Context, threadID, filename string, content []byte, caption string) error {
-				_, err := c.sendAttachmentMessageWithClientMessageID(ctx, threadID, filename, content, caption, clientMessageID)
-				return err
-			}
-			download := func(ctx context.Context, mxcURL string, file *event.EncryptedFileInfo) ([]byte, error) {
-				return c.downloadMatrixMedia(ctx, mxcURL, file)
-			}
-			err = internalbridge.HandleOutboundMatrixFile(
-				ctx,
-				msg.Portal.MXID,
-				threadID,
-				msg.Content,
-				download,
-				send,
-				&c.Login.Log,
-			)
+			// Not a GIF: send it inline through AMS (e.g. PNG/JPEG).
+			err = c.sendInlineImage(ctx, consumer, msg.Portal.MXID, threadID, msg.Content, clientMessageID, download, send)
 			break
 		}
 		_, err = consumer.SendGIFWithID(ctx, threadID, gifURL, title, c.Meta.TeamsUserID, clientMessageID)
 	case event.MsgFile:
 		// Matrix file messages are handled by downloading the MXC content and passing it to the attachment pipeline.
-		send := func(ctx context.Context, threadID, filename string, content []byte, caption string) error {
-			_, err := c.sendAttachmentMessageWithClientMessageID(ctx, threadID, filename, content, caption, clientMessageID)
-			return err
-		}
-		download := func(ctx context.Context, mxcURL string, file *event.EncryptedFileInfo) ([]byte, error) {
-			return c.downloadMatrixMedia(ctx, mxcURL, file)
-		}
 		err = internalbridge.HandleOutboundMatrixFile(
 			ctx,
 			msg.Portal.MXID,
@@ -91,14 +97,10 @@ func (c *TeamsClient) HandleMatrixMessage(ctx context.Context, msg *bridgev2.Mat
 			send,
 			&c.Login.Log,
 		)
-	case event.MsgVideo, event.MsgAudio:
-		// Treat video/audio like a normal attachment.
-		send := func(ctx context.Context, threadID, filename string, content []byte, caption string) error {
-			_, err := c.sendAttachmentMessageWithClientMessageID(ctx, threadID, filename, content, caption, clientMessageID)
-			return err
-		}
-		download := func(ctx context.Context, mxcURL string, file *event.EncryptedFileInfo) ([]byte, error) {
-			return c.downloadMatrixMedia(ctx, mxcURL, file)
+	case event.MsgAudio:
+		if msg.Content.MSC3245Voice != nil {
+			err = c.sendVoiceMessage(ctx, consumer, msg.Portal.MXID, threadID, msg.Content, clientMessageID, download, send)
+			break
 		}
 		err = internalbridge.HandleOutboundMatrixFile(
 			ctx,
@@ -109,6 +111,19 @@ func (c *TeamsClient) HandleMatrixMessage(ctx context.Context, msg *bridgev2.Mat
 			send,
 			&c.Login.Log,
 		)
+	case event.MsgLocation:
+		err = sendLocation(ctx, consumer, threadID, msg.Content, c.Meta.TeamsUserID, clientMessageID)
+	case event.MsgVideo:
+		// Treat video like a normal attachment.
+		err = internalbridge.HandleOutboundMatrixFile(
+			ctx,
+			msg.Portal.MXID,
+			threadID,
+			msg.Content,
+			download,
+			send,
+			&c.Login.Log,
+		)
 	default:
 		return nil, bridgev2.ErrUnsupportedMessageType
 	}
@@ -125,6 +140,135 @@ func (c *TeamsClient) HandleMatrixMessage(ctx context.Context, msg *bridgev2.Mat
 	}, nil
 }
 
+func (c *TeamsClient) HandleMatrixEdit(ctx context.Context, msg *bridgev2.MatrixEdit) error {
+	if !c.IsLoggedIn() {
+		return bridgev2.ErrNotLoggedIn
+	}
+	if err := c.ensureValidSkypeToken(ctx); err != nil {
+		return err
+	}
+	if msg == nil || msg.Content == nil {
+		return errors.New("missing edit content")
+	}
+	if msg.EditTarget == nil || msg.EditTarget.ID == "" {
+		return bridgev2.ErrTargetMessageNotFound
+	}
+	// Only text can be edited; attachment captions would drop the files property.
+	if msg.Content.MsgType != event.MsgText {
+		return bridgev2.ErrUnsupportedMessageType
+	}
+	threadID := strings.TrimSpace(string(msg.Portal.ID))
+	if threadID == "" {
+		return errors.New("missing thread id")
+	}
+	teamsMessageID := normalizeTeamsMessageID(string(msg.EditTarget.ID))
+	if teamsMessageID == "" {
+		return fmt.Errorf("missing teams message id for edit target %s", msg.EditTarget.ID)
+	}
+
+	consumer := c.newConsumer()
+	if consumer == nil {
+		return errors.New("missing consumer client")
+	}
+
+	// Teams replaces the whole content, so a reply has to keep its quote.
+	quote, isReply := c.buildReplyQuote(ctx, msg.Portal, c.getEditReplyTarget(ctx, msg.Portal, msg.EditTarget))
+	body := c.outboundMessageBody(ctx, msg.Content)
+	// The edit replaces the properties as well, so the subject and importance are sent again.
+	if meta, ok := msg.EditTarget.Metadata.(*teamsid.MessageMetadata); ok && meta != nil {
+		body.Subject, body.Importance = meta.Subject, model.ParseImportance(meta.Importance)
+	}
+
+	// Record before sending so a fast poll can't bounce the edit back to Matrix.
+	c.recordSelfEdit(teamsMessageID)
+	var err error
+	if isReply {
+		_, err = consumer.EditReplyMessage(ctx, threadID, teamsMessageID, body, quote, c.Meta.TeamsUserID)
+	} else {
+		_, err = consumer.EditMessage(ctx, threadID, teamsMessageID, body, c.Meta.TeamsUserID)
+	}
+	if err != nil {
+		c.consumeSelfEdit(teamsMessageID)
+		return err
+	}
+
+	// The edittime Teams assigns isn't known here, so the echo is recognised by its text.
+	editBody := strings.TrimSpace(model.NormalizeMessageBody(body.HTML).Body)
+	if meta, ok := msg.EditTarget.Metadata.(*teamsid.MessageMetadata); ok && meta != nil {
+		meta.EditBody = editBody
+	} else {
+		msg.EditTarget.Metadata = &teamsid.MessageMetadata{EditBody: editBody}
+	}
+	return nil
+}
+
+func (c *TeamsClient) HandleMatrixMessageRemove(ctx context.Context, msg *bridgev2.MatrixMessageRemove) error {
+	if !c.IsLoggedIn() {
+		return bridgev2.ErrNotLoggedIn
+	}
+	if err := c.ensureValidSkypeToken(ctx); err != nil {
+		return err
+	}
+	if msg == nil || msg.TargetMessage == nil || msg.TargetMessage.ID == "" {
+		return bridgev2.ErrTargetMessageNotFound
+	}
+	threadID := strings.TrimSpace(string(msg.Portal.ID))
+	if threadID == "" {
+		return errors.New("missing thread id")
+	}
+	teamsMessageID := normalizeTeamsMessageID(string(msg.TargetMessage.ID))
+	if teamsMessageID == "" {
+		return fmt.Errorf("missing teams message id for redaction target %s", msg.TargetMessage.ID)
+	}
+
+	consumer := c.newConsumer()
+	if consumer == nil {
+		return errors.New("missing consumer client")
+	}
+
+	// Mark first so the tombstone seen on the next poll isn't redacted again.
+	c.markDeleteSeen(string(msg.TargetMessage.ID))
+	if _, err := consumer.DeleteMessage(ctx, threadID, teamsMessageID); err != nil {
+		c.unmarkDeleteSeen(string(msg.TargetMessage.ID))
+		return err
+	}
+
+	if c.Main != nil && c.Main.Config.DeleteAttachmentFiles {
+		if meta, ok := msg.TargetMessage.Metadata.(*teamsid.MessageMetadata); ok && meta != nil && meta.DriveItemID != "" {
+			c.deleteUploadedDriveItem(ctx, meta.DriveItemID)
+		}
+	}
+	return nil
+}
+
+// deleteUploadedDriveItem is best-effort: the Teams message is already gone, so failures are only logged.
+func (c *TeamsClient) deleteUploadedDriveItem(ctx context.Context, driveItemID string) {
+	log := zerolog.Ctx(ctx).With().Str("drive_item_id", driveItemID).Logger()
+	if err := c.ensureValidGraphToken(ctx); err != nil {
+		log.Warn().Err(err).Msg("Failed to refresh graph token for attachment cleanup")
+		return
+	}
+	graphToken, err := c.Meta.GetGraphAccessToken()
+	if err != nil {
+		log.Warn().Err(err).Msg("Missing graph token for attachment cleanup")
+		return
+	}
+	httpClient := c.getConsumerHTTP()
+	if httpClient == nil {
+		return
+	}
+	gc := graph.NewClient(httpClient)
+	gc.AccessToken = graphToken
+	if c.Login != nil {
+		gc.Log = &c.Login.Log
+	}
+	if err := gc.DeleteDriveItem(ctx, driveItemID); err != nil {
+		log.Warn().Err(err).Msg("Failed to delete uploaded attachment file")
+		return
+	}
+	log.Debug().Msg("Deleted uploaded attachment file")
+}
+
 var errUnsupportedReactionEmoji = bridgev2.WrapErrorInStatus(errors.New("unsupported reaction emoji")).
 	WithErrorAsMessage().
 	WithIsCertain(true).
@@ -151,7 +295,7 @@ func (c *TeamsClient) PreHandleMatrixReaction(ctx context.Context, msg *bridgev2
 	}
 	return bridgev2.MatrixReactionPreResponse{
 		SenderID: teamsUserIDToNetworkUserID(c.Meta.TeamsUserID),
-		EmojiID:  networkid.EmojiID(emotionKey),
+		EmojiID:  MapEmotionKeyToEmojiID(emotionKey),
 		Emoji:    emoji,
 	}, nil
 }
@@ -174,20 +318,13 @@ func (c *TeamsClient) HandleMatrixReaction(ctx context.Context, msg *bridgev2.Ma
 		return nil, errors.New("missing thread id")
 	}
 
-	emotionKey := ""
 	emoji := strings.TrimSpace(msg.Content.RelatesTo.Key)
-	if msg.PreHandleResp != nil && msg.PreHandleResp.EmojiID != "" {
-		emotionKey = string(msg.PreHandleResp.EmojiID)
-		if msg.PreHandleResp.Emoji != "" {
-			emoji = msg.PreHandleResp.Emoji
-		}
+	if msg.PreHandleResp != nil && msg.PreHandleResp.Emoji != "" {
+		emoji = msg.PreHandleResp.Emoji
 	}
-	if emotionKey == "" {
-		var ok bool
-		emotionKey, ok = MapEmojiToEmotionKey(emoji)
-		if !ok {
-			return nil, errUnsupportedReactionEmoji
-		}
+	emotionKey, ok := MapEmojiToEmotionKey(emoji)
+	if !ok {
+		return nil, errUnsupportedReactionEmoji
 	}
 
 	consumer := c.newConsumer()
@@ -205,7 +342,7 @@ func (c *TeamsClient) HandleMatrixReaction(ctx context.Context, msg *bridgev2.Ma
 	}
 
 	return &database.Reaction{
-		EmojiID: networkid.EmojiID(emotionKey),
+		EmojiID: MapEmotionKeyToEmojiID(emotionKey),
 		Emoji:   emoji,
 	}, nil
 }
@@ -225,13 +362,15 @@ func (c *TeamsClient) HandleMatrixReactionRemove(ctx context.Context, msg *bridg
 		return errors.New("missing thread id")
 	}
 
-	emotionKey := strings.TrimSpace(string(msg.TargetReaction.EmojiID))
-	if emotionKey == "" {
-		var ok bool
-		emotionKey, ok = MapEmojiToEmotionKey(msg.TargetReaction.Emoji)
-		if !ok {
-			return nil
-		}
+	// Emoji IDs are stored without the name suffix Teams needs, so the key
+	// is rebuilt from the emoji.
+	emoji := msg.TargetReaction.Emoji
+	if emoji == "" {
+		emoji, _ = MapEmotionKeyToEmoji(string(msg.TargetReaction.EmojiID))
+	}
+	emotionKey, ok := MapEmojiToEmotionKey(emoji)
+	if !ok {
+		return nil
 	}
 
 	consumer := c.newConsumer()
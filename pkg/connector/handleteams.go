@@ -6,6 +6,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"net/url"
 	"strings"
 	"time"
 
@@ -15,7 +16,9 @@ import (
 	"maunium.net/go/mautrix/bridgev2/networkid"
 	"maunium.net/go/mautrix/bridgev2/simplevent"
 	"maunium.net/go/mautrix/bridgev2/status"
+	"maunium.net/go/mautrix/event"
 
+	internalbridge "go.mau.fi/mautrix-teams/internal/bridge"
 	"go.mau.fi/mautrix-teams/internal/teams/auth"
 	"go.mau.fi/mautrix-teams/internal/teams/model"
 	"go.mau.fi/mautrix-teams/pkg/teamsdb"
@@ -25,6 +28,9 @@ import (
 const (
 	threadDiscoveryInterval = 30 * time.Second
 	selfMessageTTL          = 5 * time.Minute
+	// remoteSeenTTL is how long queued edits and deletions are remembered, so
+	// copies delivered by later polls aren't queued again.
+	remoteSeenTTL = time.Hour
 )
 
 func (c *TeamsClient) startSyncLoop() {
@@ -192,7 +198,7 @@ func (c *TeamsClient) refreshThreads(ctx context.Context) error {
 		name := thread.RoomName
 		roomType := ptrRoomType(thread.IsOneToOne)
 		chatInfo := &bridgev2.ChatInfo{Name: &name, Type: roomType}
-		c.Login.QueueRemoteEvent(&simplevent.ChatResync{
+		c.queueRemoteEvent(&simplevent.ChatResync{
 			EventMeta: simplevent.EventMeta{
 				Type:         bridgev2.RemoteEventChatResync,
 				PortalKey:    c.portalKey(thread.ID),
@@ -333,6 +339,8 @@ func (c *TeamsClient) pollThread(ctx context.Context, th *teamsdb.ThreadState, n
 		selfID = model.NormalizeTeamsUserID(c.Meta.TeamsUserID)
 	}
 
+	// Calls bridged earlier in this batch aren't in the database yet.
+	queuedCalls := make(map[string]*simplevent.Message[model.RemoteMessage])
 	for _, msg := range msgs {
 		if strings.TrimSpace(msg.MessageID) == "" {
 			continue
@@ -340,8 +348,13 @@ func (c *TeamsClient) pollThread(ctx context.Context, th *teamsdb.ThreadState, n
 		effectiveMessageID := c.effectiveRemoteMessageID(msg)
 		// Filter already-seen messages in case the remote API returns history.
 		if lastSeq != "" && model.CompareSequenceID(strings.TrimSpace(msg.SequenceID), lastSeq) <= 0 {
-			// Still process reactions on older messages for sync parity.
+			if msg.IsDeleted() {
+				c.queueRemoveForMessage(ctx, th, msg, effectiveMessageID)
+				continue
+			}
+			// Still process reactions and edits on older messages for sync parity.
 			c.queueReactionSyncForMessage(ctx, th, msg, effectiveMessageID)
+			c.queueEditForMessage(ctx, th, msg, effectiveMessageID)
 			continue
 		}
 		if maxSeq == "" || model.CompareSequenceID(strings.TrimSpace(msg.SequenceID), maxSeq) > 0 {
@@ -350,6 +363,26 @@ func (c *TeamsClient) pollThread(ctx context.Context, th *teamsdb.ThreadState, n
 		if ts := msg.Timestamp.UnixMilli(); ts > maxTS {
 			maxTS = ts
 		}
+		if msg.IsDeleted() {
+			// Never bridge tombstones as new messages, only retract what was bridged.
+			c.queueRemoveForMessage(ctx, th, msg, effectiveMessageID)
+			continue
+		}
+		if msg.Kind == model.MessageKindThreadActivity {
+			if c.queueChatInfoChangeForMessage(ctx, th, msg, now) {
+				ingested++
+			}
+			continue
+		}
+		convert := c.messageConverterForKind(msg.Kind)
+		if convert == nil {
+			zerolog.Ctx(ctx).Trace().
+				Str("thread_id", th.ThreadID).
+				Str("message_id", msg.MessageID).
+				Str("message_type", msg.MessageType).
+				Msg("Dropping non-chat Teams message")
+			continue
+		}
 
 		senderID := model.NormalizeTeamsUserID(msg.SenderID)
 		if senderID == "" || strings.EqualFold(senderID, strings.TrimSpace(th.ThreadID)) || isLikelyThreadID(senderID) {
@@ -361,20 +394,23 @@ func (c *TeamsClient) pollThread(ctx context.Context, th *teamsdb.ThreadState, n
 			continue
 		}
 
-		displayName := strings.TrimSpace(msg.IMDisplayName)
-		if displayName == "" {
-			displayName = strings.TrimSpace(msg.TokenDisplayName)
-		}
-		if displayName == "" {
-			displayName = senderID
-		}
+		displayName := remoteSenderDisplayName(msg, senderID)
 		_ = c.Main.DB.Profile.Upsert(ctx, senderID, displayName, now)
 		msg.SenderName = displayName
 
-		es := bridgev2.EventSender{Sender: teamsUserIDToNetworkUserID(senderID)}
-		if senderID != "" && selfID != "" && senderID == selfID {
-			es.IsFromMe = true
-			es.SenderLogin = c.Login.ID
+		es := c.teamsEventSender(senderID)
+		if editTargetID := skypeEditTargetMessageID(msg); editTargetID != "" {
+			// Skype-style edits arrive as a new message pointing at the original,
+			// usually without an edittime of their own.
+			if msg.EditTime.IsZero() {
+				msg.EditTime = msg.Timestamp
+			}
+			if msg.EditTime.IsZero() {
+				msg.EditTime = now
+			}
+			c.queueEditForMessage(ctx, th, msg, editTargetID)
+			ingested++
+			continue
 		}
 		if effectiveMessageID != "" && len(msg.Reactions) > 0 {
 			c.markReactionSeen(effectiveMessageID, true)
@@ -388,6 +424,9 @@ func (c *TeamsClient) pollThread(ctx context.Context, th *teamsdb.ThreadState, n
 		if eventMessageID == "" {
 			eventMessageID = strings.TrimSpace(msg.MessageID)
 		}
+		if msg.Call != nil && msg.Call.CallID != "" && c.queueCallUpdate(ctx, th, msg, queuedCalls) {
+			continue
+		}
 		evt := &simplevent.Message[model.RemoteMessage]{
 			EventMeta: simplevent.EventMeta{
 				Type:         bridgev2.RemoteEventMessage,
@@ -400,10 +439,15 @@ func (c *TeamsClient) pollThread(ctx context.Context, th *teamsdb.ThreadState, n
 			Data:               msg,
 			ID:                 networkid.MessageID(eventMessageID),
 			TransactionID:      networkid.TransactionID(clientMessageID),
-			ConvertMessageFunc: c.convertTeamsMessage,
+			ConvertMessageFunc: convert,
+		}
+		c.queueRemoteEvent(evt)
+		if msg.Call != nil && msg.Call.CallID != "" {
+			queuedCalls[msg.Call.CallID] = evt
 		}
-		c.Login.QueueRemoteEvent(evt)
 		c.queueReactionSyncForMessage(ctx, th, msg, eventMessageID)
+		// The message may already be bridged if Teams bumped its sequence ID on edit.
+		c.queueEditForMessage(ctx, th, msg, eventMessageID)
 		ingested++
 		// Preserve send-intent echo reconciliation for message ID mapping while
 		// keeping unread state unchanged for self-sent events.
@@ -520,7 +564,7 @@ func (c *TeamsClient) pollConsumptionHorizons(ctx context.Context, th *teamsdb.T
 		receipt.LastTarget = targetID
 		receipt.Targets = []networkid.MessageID{targetID}
 	}
-	c.Login.QueueRemoteEvent(receipt)
+	c.queueRemoteEvent(receipt)
 
 	return c.Main.DB.ConsumptionHorizon.UpsertLastRead(ctx, c.Login.ID, threadID, remoteID, latestReadTS)
 }
@@ -575,7 +619,7 @@ func (c *TeamsClient) queueReactionSyncForMessage(ctx context.Context, th *teams
 	if messageID == "" {
 		return
 	}
-	messageID = c.resolveReactionSyncTargetMessageID(ctx, th.ThreadID, messageID, msg)
+	messageID = c.resolveRemoteTargetMessageID(ctx, th.ThreadID, messageID, msg)
 
 	data, hasReactions := c.buildReactionSyncData(msg.Reactions)
 	if !hasReactions && !c.shouldSendEmptyReactionSync(ctx, th.ThreadID, messageID) {
@@ -593,7 +637,281 @@ func (c *TeamsClient) queueReactionSyncForMessage(ctx context.Context, th *teams
 		TargetMessage: networkid.MessageID(messageID),
 		Reactions:     data,
 	}
-	c.Login.QueueRemoteEvent(evt)
+	c.queueRemoteEvent(evt)
+}
+
+func (c *TeamsClient) queueEditForMessage(ctx context.Context, th *teamsdb.ThreadState, msg model.RemoteMessage, messageID string) {
+	// Only chat text and cards are re-rendered on edit. Card messages are
+	// media-kind; Forms polls are edited in place when they close.
+	if c == nil || c.Login == nil || th == nil || !msg.IsEdited() {
+		return
+	}
+	if msg.Kind != model.MessageKindText && (msg.Kind != model.MessageKindMedia || len(msg.Cards) == 0) {
+		return
+	}
+	senderID := model.NormalizeTeamsUserID(msg.SenderID)
+	if senderID == "" || isLikelyThreadID(senderID) {
+		return
+	}
+	messageID = strings.TrimSpace(messageID)
+	if messageID == "" {
+		messageID = c.effectiveRemoteMessageID(msg)
+	}
+	if messageID == "" {
+		return
+	}
+	messageID = c.resolveRemoteTargetMessageID(ctx, th.ThreadID, messageID, msg)
+	if !c.markEditSeen(messageID, msg.EditTime.UnixMilli()) {
+		return
+	}
+	sender := c.teamsEventSender(senderID)
+	if sender.IsFromMe && c.consumeSelfEdit(messageID) {
+		// Echo of an edit that was sent from Matrix.
+		return
+	}
+	if strings.TrimSpace(msg.SenderName) == "" {
+		msg.SenderName = remoteSenderDisplayName(msg, senderID)
+	}
+
+	evt := &simplevent.Message[model.RemoteMessage]{
+		EventMeta: simplevent.EventMeta{
+			Type:      bridgev2.RemoteEventEdit,
+			PortalKey: c.portalKey(th.ThreadID),
+			Sender:    sender,
+			Timestamp: msg.EditTime,
+		},
+		Data:            msg,
+		ID:              networkid.MessageID(messageID),
+		TargetMessage:   networkid.MessageID(messageID),
+		ConvertEditFunc: c.convertTeamsEdit,
+	}
+	c.queueRemoteEvent(evt)
+}
+
+func (c *TeamsClient) queueRemoveForMessage(ctx context.Context, th *teamsdb.ThreadState, msg model.RemoteMessage, messageID string) {
+	if c == nil || c.Login == nil || th == nil || !msg.IsDeleted() {
+		return
+	}
+	messageID = strings.TrimSpace(messageID)
+	if messageID == "" {
+		messageID = c.effectiveRemoteMessageID(msg)
+	}
+	if messageID == "" {
+		return
+	}
+	messageID = c.resolveRemoteTargetMessageID(ctx, th.ThreadID, messageID, msg)
+	if !c.markDeleteSeen(messageID) {
+		return
+	}
+	timestamp := msg.DeleteTime
+	if timestamp.IsZero() {
+		timestamp = msg.EditTime
+	}
+	// bridgev2 redacts every part stored under the target message ID.
+	c.queueRemoteEvent(&simplevent.MessageRemove{
+		EventMeta: simplevent.EventMeta{
+			Type:      bridgev2.RemoteEventMessageRemove,
+			PortalKey: c.portalKey(th.ThreadID),
+			Sender:    c.teamsEventSender(model.NormalizeTeamsUserID(msg.SenderID)),
+			Timestamp: timestamp,
+		},
+		TargetMessage: networkid.MessageID(messageID),
+	})
+}
+
+func (c *TeamsClient) markDeleteSeen(messageID string) bool {
+	c.deleteSeenMu.Lock()
+	defer c.deleteSeenMu.Unlock()
+	now := time.Now()
+	if c.deleteSeen == nil {
+		c.deleteSeen = make(map[string]time.Time)
+	}
+	for id, seenAt := range c.deleteSeen {
+		if now.Sub(seenAt) > remoteSeenTTL {
+			delete(c.deleteSeen, id)
+		}
+	}
+	if _, ok := c.deleteSeen[messageID]; ok {
+		return false
+	}
+	c.deleteSeen[messageID] = now
+	return true
+}
+
+func (c *TeamsClient) unmarkDeleteSeen(messageID string) {
+	c.deleteSeenMu.Lock()
+	defer c.deleteSeenMu.Unlock()
+	delete(c.deleteSeen, messageID)
+}
+
+type seenEdit struct {
+	editMS int64
+	seenAt time.Time
+}
+
+// markEditSeen records the latest queued edit time for a message and reports
+// whether editMS is newer than anything queued before.
+func (c *TeamsClient) markEditSeen(messageID string, editMS int64) bool {
+	c.editSeenMu.Lock()
+	defer c.editSeenMu.Unlock()
+	now := time.Now()
+	if c.editSeen == nil {
+		c.editSeen = make(map[string]seenEdit)
+	}
+	for id, seen := range c.editSeen {
+		if now.Sub(seen.seenAt) > remoteSeenTTL {
+			delete(c.editSeen, id)
+		}
+	}
+	if last, ok := c.editSeen[messageID]; ok && last.editMS >= editMS {
+		return false
+	}
+	c.editSeen[messageID] = seenEdit{editMS: editMS, seenAt: now}
+	return true
+}
+
+func skypeEditTargetMessageID(msg model.RemoteMessage) string {
+	target := normalizeTeamsMessageID(msg.EditedMessageID)
+	if target == "" {
+		return ""
+	}
+	if target == normalizeTeamsMessageID(msg.MessageID) || target == strings.TrimSpace(msg.ClientMessageID) {
+		return ""
+	}
+	return target
+}
+
+func remoteSenderDisplayName(msg model.RemoteMessage, senderID string) string {
+	displayName := strings.TrimSpace(msg.IMDisplayName)
+	if displayName == "" {
+		displayName = strings.TrimSpace(msg.TokenDisplayName)
+	}
+	if displayName == "" {
+		displayName = senderID
+	}
+	return displayName
+}
+
+// queueChatInfoChangeForMessage turns a ThreadActivity system message into a
+// chat info change so membership and names follow Teams without waiting for a resync.
+func (c *TeamsClient) queueChatInfoChangeForMessage(ctx context.Context, th *teamsdb.ThreadState, msg model.RemoteMessage, now time.Time) bool {
+	if c == nil || c.Login == nil || th == nil {
+		return false
+	}
+	change := c.threadActivityChatInfoChange(msg.ThreadActivity)
+	if change == nil {
+		zerolog.Ctx(ctx).Trace().
+			Str("thread_id", th.ThreadID).
+			Str("message_id", msg.MessageID).
+			Str("message_type", msg.MessageType).
+			Msg("Ignoring unsupported Teams thread activity")
+		return false
+	}
+	activity := msg.ThreadActivity
+	if c.Main != nil && c.Main.DB != nil {
+		for _, target := range activity.Targets {
+			if target.DisplayName != "" {
+				_ = c.Main.DB.Profile.Upsert(ctx, target.ID, target.DisplayName, now)
+			}
+		}
+	}
+	initiatorID := activity.InitiatorID
+	if initiatorID == "" {
+		initiatorID = model.NormalizeTeamsUserID(msg.SenderID)
+	}
+	timestamp := activity.EventTime
+	if timestamp.IsZero() {
+		timestamp = msg.Timestamp
+	}
+	c.queueRemoteEvent(&simplevent.ChatInfoChange{
+		EventMeta: simplevent.EventMeta{
+			Type:      bridgev2.RemoteEventChatInfoChange,
+			PortalKey: c.portalKey(th.ThreadID),
+			Sender:    c.teamsEventSender(initiatorID),
+			Timestamp: timestamp,
+		},
+		ChatInfoChange: change,
+	})
+	return true
+}
+
+func (c *TeamsClient) threadActivityChatInfoChange(activity *model.ThreadActivity) *bridgev2.ChatInfoChange {
+	if activity == nil {
+		return nil
+	}
+	switch activity.Type {
+	case model.ThreadActivityTopicUpdate:
+		name := activity.Value
+		return &bridgev2.ChatInfoChange{ChatInfo: &bridgev2.ChatInfo{Name: &name}}
+	case model.ThreadActivityPictureUpdate:
+		avatar := c.threadPictureAvatar(activity.Value)
+		if avatar == nil {
+			return nil
+		}
+		return &bridgev2.ChatInfoChange{ChatInfo: &bridgev2.ChatInfo{Avatar: avatar}}
+	case model.ThreadActivityAddMember, model.ThreadActivityDeleteMember:
+		membership := event.MembershipJoin
+		if activity.Type == model.ThreadActivityDeleteMember {
+			membership = event.MembershipLeave
+		}
+		members := make(bridgev2.ChatMemberMap, len(activity.Targets))
+		for _, target := range activity.Targets {
+			member := bridgev2.ChatMember{
+				EventSender:  c.teamsEventSender(target.ID),
+				Membership:   membership,
+				MemberSender: c.teamsEventSender(activity.InitiatorID),
+			}
+			if target.DisplayName != "" {
+				name := target.DisplayName
+				member.UserInfo = &bridgev2.UserInfo{Name: &name}
+			}
+			members.Set(member)
+		}
+		return &bridgev2.ChatInfoChange{MemberChanges: &bridgev2.ChatMemberList{MemberMap: members}}
+	default:
+		return nil
+	}
+}
+
+// threadPictureAvatar returns the group picture at pictureURL, or a removal if
+// the URL is empty. Pictures are only fetched from AMS, with the skypetoken.
+func (c *TeamsClient) threadPictureAvatar(pictureURL string) *bridgev2.Avatar {
+	if pictureURL == "" {
+		return &bridgev2.Avatar{Remove: true}
+	}
+	parsed, err := url.Parse(pictureURL)
+	if err != nil || parsed.Scheme != "https" || !model.IsAMSHost(parsed.Hostname()) {
+		return nil
+	}
+	return &bridgev2.Avatar{
+		ID: networkid.AvatarID(pictureURL),
+		Get: func(ctx context.Context) ([]byte, error) {
+			consumer := c.newConsumer()
+			if consumer == nil {
+				return nil, errors.New("missing consumer client")
+			}
+			content, err := consumer.DownloadAMSObject(ctx, pictureURL, internalbridge.MaxAttachmentBytesV0)
+			if err != nil {
+				return nil, err
+			}
+			return content.Bytes, nil
+		},
+	}
+}
+
+func (c *TeamsClient) teamsEventSender(senderID string) bridgev2.EventSender {
+	es := bridgev2.EventSender{Sender: teamsUserIDToNetworkUserID(senderID)}
+	if c == nil || c.Meta == nil {
+		return es
+	}
+	selfID := model.NormalizeTeamsUserID(c.Meta.TeamsUserID)
+	if senderID != "" && selfID != "" && senderID == selfID {
+		es.IsFromMe = true
+		if c.Login != nil {
+			es.SenderLogin = c.Login.ID
+		}
+	}
+	return es
 }
 
 func (c *TeamsClient) buildReactionSyncData(reactions []model.MessageReaction) (*bridgev2.ReactionSyncData, bool) {
@@ -636,7 +954,7 @@ func (c *TeamsClient) buildReactionSyncData(reactions []model.MessageReaction) (
 			}
 			br := &bridgev2.BackfillReaction{
 				Sender:  sender,
-				EmojiID: networkid.EmojiID(emotionKey),
+				EmojiID: MapEmotionKeyToEmojiID(emotionKey),
 				Emoji:   emoji,
 			}
 			if user.TimeMS != 0 {
@@ -686,6 +1004,19 @@ func (c *TeamsClient) markReactionSeen(messageID string, seen bool) bool {
 	return exists
 }
 
+// normalizeTeamsMessageID strips the msg/ prefix some Teams payloads put on
+// message IDs.
+func normalizeTeamsMessageID(value string) string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return ""
+	}
+	if strings.HasPrefix(value, "msg/") {
+		return strings.TrimPrefix(value, "msg/")
+	}
+	return value
+}
+
 func (c *TeamsClient) effectiveRemoteMessageID(msg model.RemoteMessage) string {
 	effectiveMessageID := NormalizeTeamsReactionMessageID(msg.MessageID)
 	if effectiveMessageID == "" {
@@ -694,7 +1025,7 @@ func (c *TeamsClient) effectiveRemoteMessageID(msg model.RemoteMessage) string {
 	return effectiveMessageID
 }
 
-func (c *TeamsClient) resolveReactionSyncTargetMessageID(ctx context.Context, threadID string, messageID string, msg model.RemoteMessage) string {
+func (c *TeamsClient) resolveRemoteTargetMessageID(ctx context.Context, threadID string, messageID string, msg model.RemoteMessage) string {
 	candidates := buildReactionTargetMessageIDCandidates(messageID, msg)
 	if len(candidates) == 0 {
 		return ""
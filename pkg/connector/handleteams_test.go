@@ -0,0 +1,347 @@
+package connector
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/rs/zerolog"
+	"go.mau.fi/util/dbutil"
+	"maunium.net/go/mautrix/bridgev2"
+	"maunium.net/go/mautrix/bridgev2/database"
+	"maunium.net/go/mautrix/bridgev2/networkid"
+	"maunium.net/go/mautrix/bridgev2/simplevent"
+	"maunium.net/go/mautrix/event"
+
+	"go.mau.fi/mautrix-teams/internal/teams/model"
+	"go.mau.fi/mautrix-teams/pkg/teamsdb"
+	"go.mau.fi/mautrix-teams/pkg/teamsid"
+)
+
+func TestMarkEditSeen(t *testing.T) {
+	client := &TeamsClient{}
+	if !client.markEditSeen("m1", 100) {
+		t.Fatalf("expected first edit to be new")
+	}
+	if client.markEditSeen("m1", 100) {
+		t.Fatalf("expected repeated edit to be ignored")
+	}
+	if !client.markEditSeen("m1", 200) {
+		t.Fatalf("expected newer edit to be new")
+	}
+}
+
+func TestMarkEditSeenForgetsOldEdits(t *testing.T) {
+	client := &TeamsClient{editSeen: map[string]seenEdit{"old": {editMS: 100, seenAt: time.Now().Add(-2 * remoteSeenTTL)}}}
+	client.markEditSeen("m1", 100)
+	if _, ok := client.editSeen["old"]; ok {
+		t.Fatalf("expected expired edit to be pruned")
+	}
+}
+
+func TestSkypeEditTargetMessageID(t *testing.T) {
+	cases := []struct {
+		name string
+		msg  model.RemoteMessage
+		want string
+	}{
+		{name: "not an edit", msg: model.RemoteMessage{MessageID: "m1"}, want: ""},
+		{name: "in-place edit", msg: model.RemoteMessage{MessageID: "m1", EditedMessageID: "m1"}, want: ""},
+		{name: "own client id", msg: model.RemoteMessage{MessageID: "m2", ClientMessageID: "c2", EditedMessageID: "c2"}, want: ""},
+		{name: "edit of other message", msg: model.RemoteMessage{MessageID: "m2", EditedMessageID: "msg/m1"}, want: "m1"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := skypeEditTargetMessageID(tc.msg); got != tc.want {
+				t.Fatalf("unexpected edit target: got %q want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMarkDeleteSeen(t *testing.T) {
+	client := &TeamsClient{}
+	if !client.markDeleteSeen("m1") {
+		t.Fatalf("expected first delete to be new")
+	}
+	if client.markDeleteSeen("m1") {
+		t.Fatalf("expected repeated delete to be ignored")
+	}
+}
+
+func TestMarkDeleteSeenForgetsOldDeletes(t *testing.T) {
+	client := &TeamsClient{deleteSeen: map[string]time.Time{"old": time.Now().Add(-2 * remoteSeenTTL)}}
+	client.markDeleteSeen("m1")
+	if _, ok := client.deleteSeen["old"]; ok {
+		t.Fatalf("expected expired delete to be pruned")
+	}
+}
+
+func TestSelfEditEchoTracking(t *testing.T) {
+	client := &TeamsClient{}
+	if client.consumeSelfEdit("m1") {
+		t.Fatalf("expected no recorded edit")
+	}
+	client.recordSelfEdit("m1")
+	if !client.consumeSelfEdit("m1") {
+		t.Fatalf("expected recorded edit to be consumed")
+	}
+	if client.consumeSelfEdit("m1") {
+		t.Fatalf("expected edit to be consumed only once")
+	}
+}
+
+func TestThreadActivityChatInfoChangeMembers(t *testing.T) {
+	client := &TeamsClient{Meta: &teamsid.UserLoginMetadata{TeamsUserID: "8:live:me"}}
+	change := client.threadActivityChatInfoChange(&model.ThreadActivity{
+		Type:        model.ThreadActivityDeleteMember,
+		InitiatorID: "8:live:me",
+		Targets:     []model.ThreadActivityMember{{ID: "8:live:bob", DisplayName: "Bob"}},
+	})
+	if change == nil || change.MemberChanges == nil || change.ChatInfo != nil {
+		t.Fatalf("expected member-only change, got %#v", change)
+	}
+	member, ok := change.MemberChanges.MemberMap[networkid.UserID("8:live:bob")]
+	if !ok {
+		t.Fatalf("missing member change: %#v", change.MemberChanges.MemberMap)
+	}
+	if member.Membership != event.MembershipLeave {
+		t.Fatalf("unexpected membership: %s", member.Membership)
+	}
+	if member.UserInfo == nil || member.UserInfo.Name == nil || *member.UserInfo.Name != "Bob" {
+		t.Fatalf("unexpected user info: %#v", member.UserInfo)
+	}
+	if !member.MemberSender.IsFromMe {
+		t.Fatalf("expected initiator to be the logged-in user")
+	}
+}
+
+func TestThreadActivityChatInfoChangeTopic(t *testing.T) {
+	change := (&TeamsClient{}).threadActivityChatInfoChange(&model.ThreadActivity{
+		Type:  model.ThreadActivityTopicUpdate,
+		Value: "Launch plans",
+	})
+	if change == nil || change.ChatInfo == nil || change.ChatInfo.Name == nil || *change.ChatInfo.Name != "Launch plans" {
+		t.Fatalf("unexpected change: %#v", change)
+	}
+	if (&TeamsClient{}).threadActivityChatInfoChange(nil) != nil {
+		t.Fatalf("expected nil change for missing activity")
+	}
+}
+
+// messagesRoundTripper answers every consumer request with a fixed messages page.
+type messagesRoundTripper struct {
+	body string
+}
+
+func (rt messagesRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(rt.body)),
+		Request:    req,
+	}, nil
+}
+
+// newTestTeamsDB returns an upgraded Teams database in a private in-memory SQLite.
+func newTestTeamsDB(t *testing.T) *teamsdb.Database {
+	t.Helper()
+	rawDB, err := dbutil.NewWithDialect(":memory:", "sqlite3")
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	// Every connection to :memory: would get its own empty database.
+	rawDB.RawDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = rawDB.Close() })
+	db := teamsdb.New("teams", rawDB, zerolog.Nop())
+	if err = db.Upgrade(context.Background()); err != nil {
+		t.Fatalf("failed to upgrade test database: %v", err)
+	}
+	return db
+}
+
+// pollTestThread runs pollThread against a canned messages page and returns
+// the remote events it queued.
+func pollTestThread(t *testing.T, messages string) []bridgev2.RemoteEvent {
+	t.Helper()
+	var queued []bridgev2.RemoteEvent
+	client := &TeamsClient{
+		Main:  &TeamsConnector{DB: newTestTeamsDB(t)},
+		Login: &bridgev2.UserLogin{UserLogin: &database.UserLogin{ID: "login"}},
+		Meta: &teamsid.UserLoginMetadata{
+			TeamsUserID:         "8:live:me",
+			SkypeToken:          "token123",
+			SkypeTokenExpiresAt: time.Now().Add(time.Hour).Unix(),
+		},
+		consumerHTTP:    &http.Client{Transport: messagesRoundTripper{body: `{"messages":[` + messages + `]}`}},
+		remoteEventSink: func(evt bridgev2.RemoteEvent) { queued = append(queued, evt) },
+	}
+	th := &teamsdb.ThreadState{ThreadID: "19:thread@thread.v2", Conversation: "19:thread@thread.v2"}
+	if _, err := client.pollThread(context.Background(), th, time.Now()); err != nil {
+		t.Fatalf("pollThread failed: %v", err)
+	}
+	return queued
+}
+
+func TestPollThreadSkypeEditWithoutEditTime(t *testing.T) {
+	queued := pollTestThread(t, `{"id":"m2","sequenceId":"2","skypeeditedid":"m1","from":"8:live:alice",`+
+		`"originalarrivaltime":"2024-01-01T00:00:05Z","messagetype":"RichText/Html","content":"fixed"}`)
+	if len(queued) != 1 {
+		t.Fatalf("expected one queued event, got %d: %#v", len(queued), queued)
+	}
+	edit, ok := queued[0].(*simplevent.Message[model.RemoteMessage])
+	if !ok || edit.Type != bridgev2.RemoteEventEdit || edit.TargetMessage != "m1" {
+		t.Fatalf("expected edit of m1, got %#v", queued[0])
+	}
+	if want := time.Date(2024, 1, 1, 0, 0, 5, 0, time.UTC); !edit.Data.EditTime.Equal(want) {
+		t.Fatalf("expected arrival time as edit time, got %s", edit.Data.EditTime)
+	}
+}
+
+func TestThreadActivityChatInfoChangePicture(t *testing.T) {
+	client := &TeamsClient{}
+	pictureURL := "https://api.asm.skype.com/v1/objects/0-weu-d1-abc/views/avatar_fullsize"
+	change := client.threadActivityChatInfoChange(&model.ThreadActivity{Type: model.ThreadActivityPictureUpdate, Value: pictureURL})
+	if change == nil || change.ChatInfo == nil || change.ChatInfo.Avatar == nil {
+		t.Fatalf("expected avatar change, got %#v", change)
+	}
+	if avatar := change.ChatInfo.Avatar; avatar.ID != networkid.AvatarID(pictureURL) || avatar.Get == nil || avatar.Remove {
+		t.Fatalf("unexpected avatar: %#v", avatar)
+	}
+
+	removed := client.threadActivityChatInfoChange(&model.ThreadActivity{Type: model.ThreadActivityPictureUpdate})
+	if removed == nil || removed.ChatInfo == nil || removed.ChatInfo.Avatar == nil || !removed.ChatInfo.Avatar.Remove {
+		t.Fatalf("expected avatar removal, got %#v", removed)
+	}
+
+	if change = client.threadActivityChatInfoChange(&model.ThreadActivity{Type: model.ThreadActivityPictureUpdate, Value: "https://example.com/a.png"}); change != nil {
+		t.Fatalf("expected pictures outside AMS to be ignored, got %#v", change)
+	}
+}
+
+func TestPollThreadClosedPollCardEditEndsPoll(t *testing.T) {
+	card := `{"type":"AdaptiveCard","body":[{"type":"TextBlock","text":"Where should we eat?"},{"type":"FactSet","facts":[` +
+		`{"title":"Pizza","value":"3 votes"},{"title":"Sushi","value":"1 vote"}]}]}`
+	cards, _ := json.Marshal(`[{"contentType":"application/vnd.microsoft.card.adaptive","content":` + card + `}]`)
+	queued := pollTestThread(t, `{"id":"p1","sequenceId":"1","from":"8:live:alice","imdisplayname":"Alice",`+
+		`"originalarrivaltime":"2024-01-01T00:00:00Z","messagetype":"RichText/Media_Card","content":"",`+
+		`"properties":{"edittime":"1704067260000","cards":`+string(cards)+`}}`)
+	var edit *simplevent.Message[model.RemoteMessage]
+	for _, evt := range queued {
+		if msg, ok := evt.(*simplevent.Message[model.RemoteMessage]); ok && msg.Type == bridgev2.RemoteEventEdit {
+			edit = msg
+		}
+	}
+	if edit == nil || edit.TargetMessage != "p1" {
+		t.Fatalf("expected edit of the poll card, got %#v", queued)
+	}
+	start := &database.Message{ID: "p1", PartID: pollPartID, MXID: "$start"}
+	converted, err := edit.ConvertEditFunc(context.Background(), nil, nil, []*database.Message{start}, edit.Data)
+	if err != nil {
+		t.Fatalf("ConvertEditFunc failed: %v", err)
+	}
+	if converted.AddedParts == nil || len(converted.AddedParts.Parts) != 1 || converted.AddedParts.Parts[0].ID != pollEndPartID {
+		t.Fatalf("expected poll end part, got %#v", converted)
+	}
+}
+
+func TestPollThreadCardEditDropsURIObjectText(t *testing.T) {
+	swift := `{"type":"message/card","attachments":[{"contentType":"application/vnd.microsoft.card.hero","content":{"title":"Weather","text":"Rainy"}}]}`
+	content, _ := json.Marshal(`<URIObject type="SWIFT.1" url_thumbnail="https://example.com/thumb"><Title>Card - access it on</Title>` +
+		`<Description>Card - access it on https://go.skype.com/cards.unsupported.</Description>` +
+		`<Swift b64="` + base64.StdEncoding.EncodeToString([]byte(swift)) + `"/></URIObject>`)
+	queued := pollTestThread(t, `{"id":"c1","sequenceId":"1","from":"8:live:alice","imdisplayname":"Alice",`+
+		`"originalarrivaltime":"2024-01-01T00:00:00Z","messagetype":"RichText/Media_Card","content":`+string(content)+`,`+
+		`"properties":{"edittime":"1704067260000"}}`)
+	var edit *simplevent.Message[model.RemoteMessage]
+	for _, evt := range queued {
+		if msg, ok := evt.(*simplevent.Message[model.RemoteMessage]); ok && msg.Type == bridgev2.RemoteEventEdit {
+			edit = msg
+		}
+	}
+	if edit == nil || edit.TargetMessage != "c1" {
+		t.Fatalf("expected edit of the card, got %#v", queued)
+	}
+	existing := &database.Message{ID: "c1", Metadata: &teamsid.MessageMetadata{}}
+	converted, err := edit.ConvertEditFunc(context.Background(), nil, nil, []*database.Message{existing}, edit.Data)
+	if err != nil {
+		t.Fatalf("ConvertEditFunc failed: %v", err)
+	}
+	if len(converted.ModifiedParts) != 1 {
+		t.Fatalf("expected one modified part, got %#v", converted)
+	}
+	if body := converted.ModifiedParts[0].Content.Body; body != "Weather\nRainy" {
+		t.Fatalf("unexpected edited card body: %q", body)
+	}
+}
+
+func TestPollThreadCallEndEditsNotice(t *testing.T) {
+	started, _ := json.Marshal(`<partlist type="started" alt="" callId="call-1"><part identity="8:live:alice"><name>Alice</name></part></partlist>`)
+	ended, _ := json.Marshal(`<ended/><partlist count="1" callId="call-1"><part identity="8:live:alice"><name>Alice</name><duration>60</duration></part></partlist>`)
+	queued := pollTestThread(t, `{"id":"c1","sequenceId":"1","from":"8:live:alice","imdisplayname":"Alice",`+
+		`"originalarrivaltime":"2024-01-01T00:00:00Z","messagetype":"Event/Call","content":`+string(started)+`},`+
+		`{"id":"c2","sequenceId":"2","from":"8:live:bob","imdisplayname":"Bob",`+
+		`"originalarrivaltime":"2024-01-01T00:01:00Z","messagetype":"Event/Call","content":`+string(ended)+`}`)
+	var notice, edit *simplevent.Message[model.RemoteMessage]
+	for _, evt := range queued {
+		if msg, ok := evt.(*simplevent.Message[model.RemoteMessage]); ok && msg.Type == bridgev2.RemoteEventMessage {
+			notice = msg
+		} else if ok && msg.Type == bridgev2.RemoteEventEdit {
+			edit = msg
+		}
+	}
+	if notice == nil || notice.ID != "c1" {
+		t.Fatalf("expected the call notice under the Teams message ID, got %#v", queued)
+	}
+	if edit == nil || edit.TargetMessage != "c1" || edit.Sender.Sender != notice.Sender.Sender {
+		t.Fatalf("expected the call end to edit the notice as its sender, got %#v", edit)
+	}
+}
+
+func TestGetCallNotice(t *testing.T) {
+	rawDB, err := dbutil.NewWithDialect(":memory:", "sqlite3")
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	rawDB.RawDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = rawDB.Close() })
+	db := database.New("teams", (&TeamsConnector{}).GetDBMetaTypes(), rawDB)
+	ctx := context.Background()
+	if err = db.Upgrade(ctx); err != nil {
+		t.Fatalf("failed to upgrade database: %v", err)
+	}
+	client := &TeamsClient{
+		Main:  &TeamsConnector{Bridge: &bridgev2.Bridge{DB: db}},
+		Login: &bridgev2.UserLogin{UserLogin: &database.UserLogin{ID: "login"}},
+	}
+	th := &teamsdb.ThreadState{ThreadID: "19:thread@thread.v2"}
+	portalKey := client.portalKey(th.ThreadID)
+	if err = db.Portal.Insert(ctx, &database.Portal{PortalKey: portalKey, Metadata: &teamsid.PortalMetadata{}}); err != nil {
+		t.Fatalf("failed to insert portal: %v", err)
+	}
+	err = db.Message.Insert(ctx, &database.Message{
+		ID:        "c1",
+		Room:      portalKey,
+		SenderID:  "8:live:alice",
+		MXID:      "$notice",
+		Timestamp: time.UnixMilli(1704067200000),
+		Metadata:  &teamsid.MessageMetadata{CallerName: "Alice", CallID: "call-1"},
+	})
+	if err != nil {
+		t.Fatalf("failed to insert message: %v", err)
+	}
+
+	messageID, senderID, err := client.getCallNotice(ctx, th, "call-1")
+	if err != nil || messageID != "c1" || senderID != "8:live:alice" {
+		t.Fatalf("unexpected call notice: %q %q %v", messageID, senderID, err)
+	}
+	if messageID, _, err = client.getCallNotice(ctx, th, "call-2"); err != nil || messageID != "" {
+		t.Fatalf("expected no notice for another call, got %q %v", messageID, err)
+	}
+}
@@ -0,0 +1,64 @@
+package connector
+
+import (
+	"html"
+	"strings"
+
+	"maunium.net/go/mautrix/event"
+
+	"go.mau.fi/mautrix-teams/internal/teams/model"
+)
+
+// importanceExtraKey and subjectExtraKey carry the Teams importance flag
+// ("high" or "urgent") and subject line. Both are read back from Matrix events,
+// so clients can send important messages by setting them.
+const (
+	importanceExtraKey = "fi.mau.teams.importance"
+	subjectExtraKey    = "fi.mau.teams.subject"
+)
+
+// importanceBadge matches the labels Teams clients show on flagged messages.
+func importanceBadge(importance model.MessageImportance) string {
+	switch importance {
+	case model.ImportanceHigh:
+		return "IMPORTANT!"
+	case model.ImportanceUrgent:
+		return "URGENT!"
+	default:
+		return ""
+	}
+}
+
+// applyImportance puts the importance badge and the subject in a bold header
+// line above the content.
+func applyImportance(msg model.RemoteMessage) model.RemoteMessage {
+	badge := importanceBadge(msg.Importance)
+	subject := strings.TrimSpace(msg.Subject)
+	if badge == "" && subject == "" {
+		return msg
+	}
+	var header, headerHTML []string
+	if badge != "" {
+		header = append(header, badge)
+		headerHTML = append(headerHTML, "<strong>"+html.EscapeString(badge)+"</strong>")
+	}
+	if subject != "" {
+		header = append(header, subject)
+		headerHTML = append(headerHTML, "<strong>"+html.EscapeString(subject)+"</strong>")
+	}
+	return prependHTMLHeader(msg, strings.Join(header, " "), strings.Join(headerHTML, " "))
+}
+
+// matrixImportance reads the subject and importance fields of an outgoing Matrix event.
+func matrixImportance(evt *event.Event) (subject string, importance model.MessageImportance) {
+	if evt == nil || evt.Content.Raw == nil {
+		return "", model.ImportanceNormal
+	}
+	if value, ok := evt.Content.Raw[subjectExtraKey].(string); ok {
+		subject = strings.TrimSpace(value)
+	}
+	if value, ok := evt.Content.Raw[importanceExtraKey].(string); ok {
+		importance = model.ParseImportance(value)
+	}
+	return subject, importance
+}
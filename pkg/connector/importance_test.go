@@ -0,0 +1,114 @@
+package connector
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"maunium.net/go/mautrix/bridgev2"
+	"maunium.net/go/mautrix/bridgev2/database"
+	"maunium.net/go/mautrix/bridgev2/networkid"
+	"maunium.net/go/mautrix/event"
+
+	"go.mau.fi/mautrix-teams/internal/teams/model"
+	"go.mau.fi/mautrix-teams/pkg/teamsid"
+)
+
+func TestConvertTeamsMessageImportance(t *testing.T) {
+	tests := []struct {
+		name          string
+		msg           model.RemoteMessage
+		wantBody      string
+		wantFormatted string
+	}{
+		{
+			name:          "UrgentWithSubject",
+			msg:           model.RemoteMessage{Body: "deploy now", Subject: "Outage <prod>", Importance: model.ImportanceUrgent},
+			wantBody:      "URGENT! Outage <prod>\ndeploy now",
+			wantFormatted: "<p><strong>URGENT!</strong> <strong>Outage &lt;prod&gt;</strong></p>deploy now",
+		},
+		{
+			name:          "ImportantOnly",
+			msg:           model.RemoteMessage{Body: "read this", FormattedBody: "<p>read <b>this</b></p>", Importance: model.ImportanceHigh},
+			wantBody:      "IMPORTANT!\nread this",
+			wantFormatted: "<p><strong>IMPORTANT!</strong></p><p>read <b>this</b></p>",
+		},
+		{
+			name:          "SubjectOnly",
+			msg:           model.RemoteMessage{Body: "notes", Subject: "Weekly sync"},
+			wantBody:      "Weekly sync\nnotes",
+			wantFormatted: "<p><strong>Weekly sync</strong></p>notes",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			converted, err := (&TeamsClient{}).convertTeamsMessage(context.Background(), nil, nil, tt.msg)
+			if err != nil {
+				t.Fatalf("convert failed: %v", err)
+			}
+			part := converted.Parts[0]
+			if part.Content.Body != tt.wantBody {
+				t.Fatalf("unexpected body: %q", part.Content.Body)
+			}
+			if part.Content.FormattedBody != tt.wantFormatted {
+				t.Fatalf("unexpected formatted body: %q", part.Content.FormattedBody)
+			}
+			if tt.msg.Importance != model.ImportanceNormal && part.Extra[importanceExtraKey] != string(tt.msg.Importance) {
+				t.Fatalf("unexpected importance extra: %#v", part.Extra)
+			}
+			if tt.msg.Subject != "" && part.Extra[subjectExtraKey] != tt.msg.Subject {
+				t.Fatalf("unexpected subject extra: %#v", part.Extra)
+			}
+			meta, ok := part.DBMetadata.(*teamsid.MessageMetadata)
+			if !ok || meta.Subject != tt.msg.Subject || meta.Importance != string(tt.msg.Importance) {
+				t.Fatalf("unexpected part metadata: %#v", part.DBMetadata)
+			}
+		})
+	}
+}
+
+func TestMatrixImportance(t *testing.T) {
+	subject, importance := matrixImportance(&event.Event{Content: event.Content{Raw: map[string]any{
+		subjectExtraKey:    " Launch ",
+		importanceExtraKey: "important",
+	}}})
+	if subject != "Launch" || importance != model.ImportanceHigh {
+		t.Fatalf("unexpected subject/importance: %q %q", subject, importance)
+	}
+	if subject, importance = matrixImportance(&event.Event{Content: event.Content{Raw: map[string]any{"body": "x"}}}); subject != "" || importance != model.ImportanceNormal {
+		t.Fatalf("expected plain message to have no importance: %q %q", subject, importance)
+	}
+}
+
+func TestHandleMatrixEditKeepsSubjectAndImportance(t *testing.T) {
+	var bodies []string
+	client := &TeamsClient{
+		Login: &bridgev2.UserLogin{UserLogin: &database.UserLogin{ID: "login"}},
+		Meta: &teamsid.UserLoginMetadata{
+			TeamsUserID:         "8:live:me",
+			SkypeToken:          "token123",
+			SkypeTokenExpiresAt: time.Now().Add(time.Hour).Unix(),
+		},
+		consumerHTTP: &http.Client{Transport: reactionRoundTripper{bodies: &bodies}},
+	}
+	portal := &bridgev2.Portal{Portal: &database.Portal{PortalKey: networkid.PortalKey{ID: "19:thread@thread.v2"}}}
+	target := &database.Message{ID: "m1", Metadata: &teamsid.MessageMetadata{Subject: "Launch", Importance: string(model.ImportanceUrgent)}}
+	err := client.HandleMatrixEdit(context.Background(), &bridgev2.MatrixEdit{
+		MatrixEventBase: bridgev2.MatrixEventBase[*event.MessageEventContent]{
+			Content: &event.MessageEventContent{MsgType: event.MsgText, Body: "fixed"},
+			Portal:  portal,
+		},
+		EditTarget: target,
+	})
+	if err != nil {
+		t.Fatalf("HandleMatrixEdit failed: %v", err)
+	}
+	if len(bodies) != 1 || !strings.Contains(bodies[0], `"subject":"Launch"`) || !strings.Contains(bodies[0], `"importance":"urgent"`) {
+		t.Fatalf("expected the subject and importance to be sent again, got %q", bodies)
+	}
+	if meta := target.Metadata.(*teamsid.MessageMetadata); meta.Subject != "Launch" || meta.EditBody != "fixed" {
+		t.Fatalf("unexpected target metadata: %#v", meta)
+	}
+}
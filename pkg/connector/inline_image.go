@@ -0,0 +1,82 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"strings"
+
+	"github.com/rs/zerolog"
+	"maunium.net/go/mautrix/bridgev2"
+	"maunium.net/go/mautrix/bridgev2/networkid"
+	"maunium.net/go/mautrix/event"
+	"maunium.net/go/mautrix/id"
+
+	internalbridge "go.mau.fi/mautrix-teams/internal/bridge"
+	"go.mau.fi/mautrix-teams/internal/teams/model"
+)
+
+// reuploadInlineImages downloads pasted AMS images with the skypetoken and
+// re-uploads them as m.image parts. Images that fail are returned so the
+// caption can at least mention them.
+func (c *TeamsClient) reuploadInlineImages(
+	ctx context.Context,
+	roomID id.RoomID,
+	intent bridgev2.MatrixAPI,
+	images []model.InlineImage,
+	extra map[string]any,
+) (parts []*bridgev2.ConvertedMessagePart, failed []model.InlineImage) {
+	consumer := c.newConsumer()
+	if consumer == nil {
+		return nil, images
+	}
+	log := zerolog.Ctx(ctx)
+	for i, image := range images {
+		content, err := consumer.DownloadAMSObject(ctx, image.URL, internalbridge.MaxAttachmentBytesV0)
+		if err != nil || content == nil || len(content.Bytes) == 0 {
+			log.Warn().Err(err).Str("ams_object_id", image.ObjectID).Msg("Failed to download inline Teams image")
+			failed = append(failed, image)
+			continue
+		}
+		mimeType := detectMIMEType("", content.ContentType, content.Bytes)
+		filename := inlineImageFilename(mimeType)
+		mxc, file, err := intent.UploadMedia(ctx, roomID, content.Bytes, filename, mimeType)
+		if err != nil {
+			log.Warn().Err(err).Str("ams_object_id", image.ObjectID).Msg("Failed to upload inline Teams image")
+			failed = append(failed, image)
+			continue
+		}
+		media := buildMediaContent(matrixMsgTypeForMIME(mimeType), filename, mimeType, len(content.Bytes), mxc, file)
+		media.Info.Width = image.Width
+		media.Info.Height = image.Height
+		parts = append(parts, &bridgev2.ConvertedMessagePart{
+			ID:      networkid.PartID(fmt.Sprintf("img_%d", i)),
+			Type:    event.EventMessage,
+			Extra:   cloneExtra(extra),
+			Content: media,
+		})
+	}
+	return parts, failed
+}
+
+func inlineImageFilename(mimeType string) string {
+	if exts, err := mime.ExtensionsByType(mimeType); err == nil && len(exts) > 0 {
+		return "image" + exts[0]
+	}
+	return "image"
+}
+
+func renderFailedInlineImages(rendered renderedInboundMessage, failed []model.InlineImage) renderedInboundMessage {
+	if len(failed) == 0 {
+		return rendered
+	}
+	note := "[image could not be bridged]"
+	if len(failed) > 1 {
+		note = fmt.Sprintf("[%d images could not be bridged]", len(failed))
+	}
+	if rendered.FormattedBody != "" {
+		rendered.FormattedBody += "<br><em>" + note + "</em>"
+	}
+	rendered.Body = strings.TrimSpace(rendered.Body + "\n" + note)
+	return rendered
+}
@@ -0,0 +1,114 @@
+package connector
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+	"maunium.net/go/mautrix/bridgev2"
+	"maunium.net/go/mautrix/event"
+	"maunium.net/go/mautrix/id"
+
+	"go.mau.fi/mautrix-teams/internal/teams/model"
+)
+
+// maxLinkPreviewImageBytes keeps preview thumbnails small; Teams only generates
+// small images anyway.
+const maxLinkPreviewImageBytes = 5 * 1024 * 1024
+
+// convertLinkPreviews turns Teams URL previews into com.beeper.linkpreviews entries.
+// Thumbnails are re-uploaded when possible; a preview without its image is
+// still better than none. previewurl is chosen by the sender, so only
+// thumbnails proxied through AMS are fetched. Thumbnails already bridged in
+// previous (the previews of the message being edited) are reused.
+func (c *TeamsClient) convertLinkPreviews(ctx context.Context, portal *bridgev2.Portal, intent bridgev2.MatrixAPI, previews []model.LinkPreview, previous []*event.BeeperLinkPreview) []*event.BeeperLinkPreview {
+	if len(previews) == 0 {
+		return nil
+	}
+	out := make([]*event.BeeperLinkPreview, 0, len(previews))
+	for _, preview := range previews {
+		converted := &event.BeeperLinkPreview{
+			MatchedURL: preview.URL,
+			LinkPreview: event.LinkPreview{
+				CanonicalURL: preview.URL,
+				Title:        preview.Title,
+				Description:  preview.Description,
+			},
+		}
+		if prev := findLinkPreview(previous, preview.URL); preview.ImageURL != "" && prev != nil && prev.ImageURL != "" {
+			copyLinkPreviewImage(converted, prev)
+		} else if preview.ImageURL != "" && intent != nil && c != nil {
+			if err := c.reuploadLinkPreviewImage(ctx, portal, intent, preview, converted); err != nil {
+				zerolog.Ctx(ctx).Debug().Err(err).Str("url", preview.URL).Msg("Failed to bridge link preview image")
+			}
+		}
+		out = append(out, converted)
+	}
+	return out
+}
+
+func (c *TeamsClient) reuploadLinkPreviewImage(ctx context.Context, portal *bridgev2.Portal, intent bridgev2.MatrixAPI, preview model.LinkPreview, converted *event.BeeperLinkPreview) error {
+	consumer := c.newConsumer()
+	if consumer == nil {
+		return nil
+	}
+	content, err := consumer.DownloadAMSObject(ctx, preview.ImageURL, maxLinkPreviewImageBytes)
+	if err != nil {
+		return err
+	}
+	mimeType := detectMIMEType("", content.ContentType, content.Bytes)
+	roomID := id.RoomID("")
+	if portal != nil {
+		roomID = portal.MXID
+	}
+	mxc, file, err := intent.UploadMedia(ctx, roomID, content.Bytes, "", mimeType)
+	if err != nil {
+		return err
+	}
+	if file != nil {
+		converted.ImageEncryption = file
+		converted.ImageURL = file.URL
+	} else {
+		converted.ImageURL = mxc
+	}
+	converted.ImageType = mimeType
+	converted.ImageSize = event.IntOrString(len(content.Bytes))
+	converted.ImageWidth = event.IntOrString(preview.ImageWidth)
+	converted.ImageHeight = event.IntOrString(preview.ImageHeight)
+	return nil
+}
+
+func findLinkPreview(previews []*event.BeeperLinkPreview, matchedURL string) *event.BeeperLinkPreview {
+	for _, preview := range previews {
+		if preview != nil && preview.MatchedURL == matchedURL {
+			return preview
+		}
+	}
+	return nil
+}
+
+func copyLinkPreviewImage(dst, src *event.BeeperLinkPreview) {
+	dst.ImageURL = src.ImageURL
+	dst.ImageEncryption = src.ImageEncryption
+	dst.ImageType = src.ImageType
+	dst.ImageSize = src.ImageSize
+	dst.ImageWidth = src.ImageWidth
+	dst.ImageHeight = src.ImageHeight
+}
+
+// setTextPartLinkPreviews attaches the previews to the first text part and
+// records them in its metadata.
+func setTextPartLinkPreviews(parts []*bridgev2.ConvertedMessagePart, previews []*event.BeeperLinkPreview) {
+	if len(previews) == 0 {
+		return
+	}
+	for _, part := range parts {
+		if part == nil || part.Content == nil {
+			continue
+		}
+		if part.Content.MsgType == event.MsgText || part.Content.MsgType == event.MsgNotice {
+			part.Content.BeeperLinkPreviews = previews
+			partMetadata(part).LinkPreviews = previews
+			return
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package connector
+
+import (
+	"context"
+	"errors"
+	"html"
+	"strings"
+
+	"maunium.net/go/mautrix/bridgev2"
+	"maunium.net/go/mautrix/event"
+
+	consumerclient "go.mau.fi/mautrix-teams/internal/teams/client"
+	"go.mau.fi/mautrix-teams/internal/teams/model"
+)
+
+// convertTeamsLocationMessage bridges a shared location as m.location.
+func convertTeamsLocationMessage(msg model.RemoteMessage) *bridgev2.ConvertedMessage {
+	location := msg.Location
+	geoURI := location.GeoURI()
+	description := location.Description()
+	body := "Location: " + geoURI
+	if description != "" {
+		body = "Location: " + description + " (" + geoURI + ")"
+	}
+	extra := perMessageExtra(msg)
+	if extra == nil {
+		extra = make(map[string]any, 1)
+	}
+	extra["org.matrix.msc3488.location"] = map[string]any{
+		"uri":         geoURI,
+		"description": description,
+	}
+	return &bridgev2.ConvertedMessage{
+		Parts: []*bridgev2.ConvertedMessagePart{{
+			Type: event.EventMessage,
+			Content: &event.MessageEventContent{
+				MsgType: event.MsgLocation,
+				Body:    body,
+				GeoURI:  geoURI,
+			},
+			Extra: extra,
+		}},
+	}
+}
+
+// convertTeamsContactsMessage renders shared contact cards as vCard text.
+func convertTeamsContactsMessage(msg model.RemoteMessage) *bridgev2.ConvertedMessage {
+	names := make([]string, 0, len(msg.Contacts))
+	namesHTML := make([]string, 0, len(msg.Contacts))
+	vcards := make([]string, 0, len(msg.Contacts))
+	for _, contact := range msg.Contacts {
+		names = append(names, contact.Name)
+		namesHTML = append(namesHTML, "<strong>"+html.EscapeString(contact.Name)+"</strong>")
+		vcards = append(vcards, contact.VCard())
+	}
+	header := "Shared contact: "
+	if len(msg.Contacts) > 1 {
+		header = "Shared contacts: "
+	}
+	vcardText := strings.Join(vcards, "\n")
+	return &bridgev2.ConvertedMessage{
+		Parts: []*bridgev2.ConvertedMessagePart{{
+			Type: event.EventMessage,
+			Content: &event.MessageEventContent{
+				MsgType:       event.MsgText,
+				Body:          header + strings.Join(names, ", ") + "\n\n" + vcardText,
+				Format:        event.FormatHTML,
+				FormattedBody: "<p>" + header + strings.Join(namesHTML, ", ") + "</p><pre><code>" + html.EscapeString(vcardText) + "</code></pre>",
+			},
+			Extra: perMessageExtra(msg),
+		}},
+	}
+}
+
+// sendLocation sends an m.location event to Teams as a location card. The body
+// is used as the place name unless it's just the fallback text of the geo URI.
+func sendLocation(ctx context.Context, consumer *consumerclient.Client, threadID string, content *event.MessageEventContent, fromUserID string, clientMessageID string) error {
+	latitude, longitude, ok := model.ParseGeoURI(content.GeoURI)
+	if !ok {
+		return errors.New("invalid geo uri")
+	}
+	location := model.Location{Latitude: latitude, Longitude: longitude}
+	if name := strings.TrimSpace(content.Body); name != "" && !strings.Contains(name, "geo:") {
+		location.Name = name
+	}
+	_, err := consumer.SendLocationWithID(ctx, threadID, location, fromUserID, clientMessageID)
+	return err
+}
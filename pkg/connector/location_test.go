@@ -0,0 +1,48 @@
+package connector
+
+import (
+	"context"
+	"testing"
+
+	"maunium.net/go/mautrix/event"
+
+	"go.mau.fi/mautrix-teams/internal/teams/model"
+)
+
+func TestConvertTeamsLocationMessage(t *testing.T) {
+	msg := model.RemoteMessage{
+		Kind:     model.MessageKindMedia,
+		Location: &model.Location{Latitude: 47.640061, Longitude: -122.129797, Name: "Building 92", Address: "Redmond"},
+	}
+	converted, err := (&TeamsClient{}).convertTeamsMediaMessage(context.Background(), nil, nil, msg)
+	if err != nil {
+		t.Fatalf("convert failed: %v", err)
+	}
+	content := converted.Parts[0].Content
+	if content.MsgType != event.MsgLocation || content.GeoURI != "geo:47.640061,-122.129797" {
+		t.Fatalf("unexpected content: %#v", content)
+	}
+	if content.Body != "Location: Building 92, Redmond (geo:47.640061,-122.129797)" {
+		t.Fatalf("unexpected body: %q", content.Body)
+	}
+}
+
+func TestConvertTeamsContactsMessage(t *testing.T) {
+	msg := model.RemoteMessage{
+		Kind:     model.MessageKindMedia,
+		Contacts: []model.Contact{{UserID: "8:live:alice", Name: "Alice <A>"}},
+	}
+	converted, err := (&TeamsClient{}).convertTeamsMediaMessage(context.Background(), nil, nil, msg)
+	if err != nil {
+		t.Fatalf("convert failed: %v", err)
+	}
+	content := converted.Parts[0].Content
+	wantBody := "Shared contact: Alice <A>\n\nBEGIN:VCARD\nVERSION:3.0\nFN:Alice <A>\nX-MSTEAMS-ID:8:live:alice\nEND:VCARD"
+	if content.MsgType != event.MsgText || content.Body != wantBody {
+		t.Fatalf("unexpected body: %q", content.Body)
+	}
+	wantHTML := "<p>Shared contact: <strong>Alice &lt;A&gt;</strong></p><pre><code>BEGIN:VCARD\nVERSION:3.0\nFN:Alice &lt;A&gt;\nX-MSTEAMS-ID:8:live:alice\nEND:VCARD</code></pre>"
+	if content.FormattedBody != wantHTML {
+		t.Fatalf("unexpected html: %q", content.FormattedBody)
+	}
+}
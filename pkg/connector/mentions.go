@@ -0,0 +1,164 @@
+package connector
+
+import (
+	"context"
+	"strings"
+
+	"maunium.net/go/mautrix/bridgev2"
+	"maunium.net/go/mautrix/event"
+	"maunium.net/go/mautrix/format"
+	"maunium.net/go/mautrix/id"
+
+	consumerclient "go.mau.fi/mautrix-teams/internal/teams/client"
+	"go.mau.fi/mautrix-teams/internal/teams/model"
+)
+
+// mentionMXID maps a mentioned Teams user to the logged-in Matrix user or their ghost.
+func (c *TeamsClient) mentionMXID(mri string) id.UserID {
+	mri = model.NormalizeTeamsUserID(mri)
+	if c == nil || mri == "" {
+		return ""
+	}
+	if c.Meta != nil && mri == model.NormalizeTeamsUserID(c.Meta.TeamsUserID) && c.Login != nil && c.Login.UserLogin != nil {
+		return c.Login.UserMXID
+	}
+	if c.Main == nil || c.Main.Bridge == nil || c.Main.Bridge.Matrix == nil {
+		return ""
+	}
+	return c.Main.Bridge.Matrix.GhostIntent(teamsUserIDToNetworkUserID(mri)).GetMXID()
+}
+
+// applyMentions re-renders the message body with the mentions that resolve to
+// Matrix users as matrix.to pills.
+func (c *TeamsClient) applyMentions(msg model.RemoteMessage) (model.RemoteMessage, *event.Mentions) {
+	if msg.Kind != model.MessageKindText || len(msg.Mentions) == 0 || msg.ContentHTML == "" {
+		return msg, nil
+	}
+	byItemID := make(map[string]id.UserID, len(msg.Mentions))
+	for _, mention := range msg.Mentions {
+		if mxid := c.mentionMXID(mention.MRI); mxid != "" {
+			byItemID[mention.ItemID] = mxid
+		}
+	}
+	if len(byItemID) == 0 {
+		return msg, nil
+	}
+
+	var mentions event.Mentions
+	content := model.NormalizeMessageBodyWithMentions(msg.ContentHTML, func(itemID string) (string, bool) {
+		mxid, ok := byItemID[itemID]
+		if !ok {
+			return "", false
+		}
+		mentions.Add(mxid)
+		return mxid.URI().MatrixToURL(), true
+	})
+	msg.Body = content.Body
+	msg.FormattedBody = content.FormattedBody
+	if len(mentions.UserIDs) == 0 {
+		return msg, nil
+	}
+	return msg, &mentions
+}
+
+func setTextPartMentions(parts []*bridgev2.ConvertedMessagePart, mentions *event.Mentions) {
+	if mentions == nil {
+		return
+	}
+	for _, part := range parts {
+		if part == nil || part.Content == nil {
+			continue
+		}
+		if part.Content.MsgType == event.MsgText || part.Content.MsgType == event.MsgNotice {
+			part.Content.Mentions = mentions
+		}
+	}
+}
+
+// mentionMRI is the reverse of mentionMXID: it maps a mentioned Matrix user to
+// the logged-in Teams user or to the Teams user behind a ghost.
+func (c *TeamsClient) mentionMRI(mxid id.UserID) string {
+	if c == nil || mxid == "" {
+		return ""
+	}
+	if c.Meta != nil && c.Login != nil && c.Login.UserLogin != nil && mxid == c.Login.UserMXID {
+		return model.NormalizeTeamsUserID(c.Meta.TeamsUserID)
+	}
+	if c.Main == nil || c.Main.Bridge == nil || c.Main.Bridge.Matrix == nil {
+		return ""
+	}
+	ghostID, ok := c.Main.Bridge.Matrix.ParseGhostMXID(mxid)
+	if !ok {
+		return ""
+	}
+	return model.NormalizeTeamsUserID(string(ghostID))
+}
+
+// pillMRI resolves a matrix.to or matrix: user link to a Teams MRI.
+func (c *TeamsClient) pillMRI(href string) string {
+	uri, err := id.ParseMatrixURIOrMatrixToURL(href)
+	if err != nil || uri.Sigil1 != '@' {
+		return ""
+	}
+	return c.mentionMRI(uri.UserID())
+}
+
+// outboundMessageBody renders a Matrix text message as Teams HTML, keeping the
+// sender's formatting when there is a formatted_body. Plain bodies are rendered
+// as Markdown when render_markdown is enabled.
+func (c *TeamsClient) outboundMessageBody(ctx context.Context, content *event.MessageEventContent) consumerclient.MessageBody {
+	if content.Format == event.FormatHTML && strings.TrimSpace(content.FormattedBody) != "" {
+		converted, mentions := model.ConvertMatrixHTML(content.FormattedBody, c.pillMRI)
+		if converted != "" {
+			return consumerclient.MessageBody{HTML: converted, Mentions: mentions}
+		}
+	}
+	mentions := c.outboundMentions(ctx, content)
+	// Name-based mentions can only be placed in plain text, and a notification
+	// matters more than formatting.
+	if len(mentions) == 0 && c.Main != nil && c.Main.Config.RenderMarkdown {
+		rendered := format.RenderMarkdown(content.Body, true, false)
+		if rendered.FormattedBody != "" {
+			if converted, _ := model.ConvertMatrixHTML(rendered.FormattedBody, nil); converted != "" {
+				return consumerclient.MessageBody{HTML: converted}
+			}
+		}
+	}
+	return consumerclient.PlainTextBody(content.Body, mentions)
+}
+
+// outboundMentions collects Teams mentions for a plain Matrix message from
+// m.mentions. Users fall back to their cached Teams display name, so they only
+// become mentions if that name is in the body.
+func (c *TeamsClient) outboundMentions(ctx context.Context, content *event.MessageEventContent) []consumerclient.Mention {
+	if content == nil || content.Mentions == nil {
+		return nil
+	}
+	var mentions []consumerclient.Mention
+	seen := make(map[id.UserID]bool)
+	for _, mxid := range content.Mentions.UserIDs {
+		if seen[mxid] {
+			continue
+		}
+		mri := c.mentionMRI(mxid)
+		if mri == "" {
+			continue
+		}
+		seen[mxid] = true
+		if name := c.cachedDisplayName(ctx, mri); name != "" {
+			mentions = append(mentions, consumerclient.Mention{MRI: mri, DisplayName: name})
+		}
+	}
+	return mentions
+}
+
+func (c *TeamsClient) cachedDisplayName(ctx context.Context, teamsUserID string) string {
+	if c == nil || c.Main == nil || c.Main.DB == nil || c.Main.DB.Profile == nil {
+		return ""
+	}
+	profile, err := c.Main.DB.Profile.GetByTeamsUserID(ctx, teamsUserID)
+	if err != nil || profile == nil {
+		return ""
+	}
+	return strings.TrimSpace(profile.DisplayName)
+}
@@ -0,0 +1,121 @@
+package connector
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"maunium.net/go/mautrix/bridgev2"
+	"maunium.net/go/mautrix/bridgev2/database"
+	"maunium.net/go/mautrix/event"
+	"maunium.net/go/mautrix/id"
+
+	"go.mau.fi/mautrix-teams/internal/teams/model"
+	"go.mau.fi/mautrix-teams/pkg/teamsid"
+)
+
+func newMentionTestClient() *TeamsClient {
+	return &TeamsClient{
+		Login: &bridgev2.UserLogin{UserLogin: &database.UserLogin{UserMXID: id.UserID("@me:example.org")}},
+		Meta:  &teamsid.UserLoginMetadata{TeamsUserID: "8:live:me"},
+	}
+}
+
+func TestApplyMentionsRendersPillsAndMergesSplitNames(t *testing.T) {
+	msg := model.RemoteMessage{
+		ContentHTML: `<p>hey <span itemscope="" itemtype="http://schema.skype.com/Mention" itemid="0">My</span> ` +
+			`<span itemscope="" itemtype="http://schema.skype.com/Mention" itemid="1">Name</span>, look</p>`,
+		Mentions: []model.MessageMention{
+			{ItemID: "0", MRI: "8:live:me"},
+			{ItemID: "1", MRI: "8:live:me"},
+		},
+	}
+	got, mentions := newMentionTestClient().applyMentions(msg)
+	want := `<p>hey <a href="https://matrix.to/#/@me:example.org">My Name</a>, look</p>`
+	if got.FormattedBody != want {
+		t.Fatalf("unexpected formatted body:\nwant: %s\ngot:  %s", want, got.FormattedBody)
+	}
+	if got.Body != "hey My Name, look" {
+		t.Fatalf("unexpected body: %q", got.Body)
+	}
+	if mentions == nil || len(mentions.UserIDs) != 1 || mentions.UserIDs[0] != "@me:example.org" {
+		t.Fatalf("unexpected mentions: %#v", mentions)
+	}
+}
+
+func TestApplyMentionsInsideColoredText(t *testing.T) {
+	msg := model.RemoteMessage{
+		ContentHTML: `<p><span style="color:#ff0000">hi <span itemscope="" itemtype="http://schema.skype.com/Mention" itemid="0">Me</span></span> there</p>`,
+		Mentions:    []model.MessageMention{{ItemID: "0", MRI: "8:live:me"}},
+	}
+	got, _ := newMentionTestClient().applyMentions(msg)
+	want := `<p><span data-mx-color="#ff0000">hi <a href="https://matrix.to/#/@me:example.org">Me</a></span> there</p>`
+	if got.FormattedBody != want {
+		t.Fatalf("unexpected formatted body:\nwant: %s\ngot:  %s", want, got.FormattedBody)
+	}
+}
+
+func TestApplyMentionsKeepsUnresolvedMentionsAsText(t *testing.T) {
+	msg := model.RemoteMessage{
+		Body:        "hi Alice",
+		ContentHTML: `<p>hi <span itemscope="" itemtype="http://schema.skype.com/Mention" itemid="0">Alice</span></p>`,
+		Mentions:    []model.MessageMention{{ItemID: "0", MRI: "8:live:alice"}},
+	}
+	got, mentions := newMentionTestClient().applyMentions(msg)
+	if got.Body != "hi Alice" || got.FormattedBody != "" {
+		t.Fatalf("unexpected content: %q %q", got.Body, got.FormattedBody)
+	}
+	if mentions != nil {
+		t.Fatalf("expected no mentions, got %#v", mentions)
+	}
+}
+
+func TestOutboundMentionsFromMentionsList(t *testing.T) {
+	client := newMentionTestClient()
+	client.Main = &TeamsConnector{DB: newTestTeamsDB(t)}
+	if err := client.Main.DB.Profile.Upsert(context.Background(), "8:live:me", "Me", time.Now()); err != nil {
+		t.Fatalf("failed to store profile: %v", err)
+	}
+	content := &event.MessageEventContent{
+		MsgType:  event.MsgText,
+		Body:     "Me: hi",
+		Mentions: &event.Mentions{UserIDs: []id.UserID{"@me:example.org", "@stranger:example.org"}},
+	}
+	got := client.outboundMentions(context.Background(), content)
+	if len(got) != 1 || got[0].MRI != "8:live:me" || got[0].DisplayName != "Me" {
+		t.Fatalf("unexpected mentions: %#v", got)
+	}
+}
+
+func TestOutboundMessageBodyConvertsFormattedBody(t *testing.T) {
+	content := &event.MessageEventContent{
+		MsgType:       event.MsgText,
+		Body:          "**Me** hi",
+		Format:        event.FormatHTML,
+		FormattedBody: `<strong><a href="https://matrix.to/#/@me:example.org">Me</a></strong> hi`,
+	}
+	got := newMentionTestClient().outboundMessageBody(context.Background(), content)
+	want := `<p><strong><span itemscope="" itemtype="http://schema.skype.com/Mention" itemid="0">Me</span></strong> hi</p>`
+	if got.HTML != want {
+		t.Fatalf("unexpected html:\nwant: %s\ngot:  %s", want, got.HTML)
+	}
+	if len(got.Mentions) != 1 || got.Mentions[0].MRI != "8:live:me" {
+		t.Fatalf("unexpected mentions: %#v", got.Mentions)
+	}
+}
+
+func TestOutboundMessageBodyRendersMarkdownWhenEnabled(t *testing.T) {
+	content := &event.MessageEventContent{MsgType: event.MsgText, Body: "**bold** and `code`"}
+	client := newMentionTestClient()
+	if got := client.outboundMessageBody(context.Background(), content); got.HTML != "<p>**bold** and `code`</p>" {
+		t.Fatalf("markdown rendered while disabled: %q", got.HTML)
+	}
+	client.Main = &TeamsConnector{Config: TeamsConfig{RenderMarkdown: true}}
+	if got := client.outboundMessageBody(context.Background(), content); got.HTML != "<p><strong>bold</strong> and <code>code</code></p>" {
+		t.Fatalf("unexpected markdown html: %q", got.HTML)
+	}
+	plain := &event.MessageEventContent{MsgType: event.MsgText, Body: "just text"}
+	if got := client.outboundMessageBody(context.Background(), plain); got.HTML != "<p>just text</p>" {
+		t.Fatalf("unexpected plain html: %q", got.HTML)
+	}
+}
@@ -0,0 +1,68 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"maunium.net/go/mautrix/bridgev2"
+	"maunium.net/go/mautrix/event"
+
+	"go.mau.fi/mautrix-teams/internal/teams/model"
+)
+
+type teamsMessageConverter func(ctx context.Context, portal *bridgev2.Portal, intent bridgev2.MatrixAPI, msg model.RemoteMessage) (*bridgev2.ConvertedMessage, error)
+
+// messageConverterForKind picks the converter for a Teams message kind.
+// A nil converter means the kind never becomes a Matrix room message.
+func (c *TeamsClient) messageConverterForKind(kind model.MessageKind) teamsMessageConverter {
+	switch kind {
+	case model.MessageKindText:
+		return c.convertTeamsMessage
+	case model.MessageKindMedia:
+		return c.convertTeamsMediaMessage
+	case model.MessageKindCall:
+		return c.convertTeamsCallMessage
+	case model.MessageKindControl, model.MessageKindThreadActivity:
+		return nil
+	default:
+		return c.convertTeamsUnsupportedMessage
+	}
+}
+
+func (c *TeamsClient) convertTeamsMediaMessage(ctx context.Context, portal *bridgev2.Portal, intent bridgev2.MatrixAPI, msg model.RemoteMessage) (*bridgev2.ConvertedMessage, error) {
+	if msg.Audio != nil {
+		return c.convertTeamsAudioMessage(ctx, portal, intent, msg)
+	}
+	if msg.Location != nil {
+		return convertTeamsLocationMessage(msg), nil
+	}
+	if len(msg.Contacts) > 0 {
+		return convertTeamsContactsMessage(msg), nil
+	}
+	if strings.TrimSpace(msg.PropertiesFiles) == "" && len(msg.Cards) == 0 {
+		return c.convertTeamsUnsupportedMessage(ctx, portal, intent, msg)
+	}
+	// The content is URIObject XML describing the files or cards, which are bridged
+	// from properties.files and the parsed cards instead.
+	msg.Body = ""
+	msg.FormattedBody = ""
+	return c.convertTeamsMessage(ctx, portal, intent, msg)
+}
+
+func (c *TeamsClient) convertTeamsUnsupportedMessage(ctx context.Context, portal *bridgev2.Portal, intent bridgev2.MatrixAPI, msg model.RemoteMessage) (*bridgev2.ConvertedMessage, error) {
+	messageType := strings.TrimSpace(msg.MessageType)
+	if messageType == "" {
+		messageType = msg.Kind.String()
+	}
+	return &bridgev2.ConvertedMessage{
+		Parts: []*bridgev2.ConvertedMessagePart{{
+			Type: event.EventMessage,
+			Content: &event.MessageEventContent{
+				MsgType: event.MsgNotice,
+				Body:    fmt.Sprintf("Unsupported Teams message (%s)", messageType),
+			},
+			Extra: perMessageExtra(msg),
+		}},
+	}, nil
+}
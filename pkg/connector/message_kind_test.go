@@ -0,0 +1,141 @@
+package connector
+
+import (
+	"context"
+	"testing"
+
+	"maunium.net/go/mautrix/bridgev2/database"
+	"maunium.net/go/mautrix/event"
+
+	"go.mau.fi/mautrix-teams/internal/teams/model"
+	"go.mau.fi/mautrix-teams/pkg/teamsid"
+)
+
+func TestMessageConverterForKindDropsNonChatKinds(t *testing.T) {
+	client := &TeamsClient{}
+	for _, kind := range []model.MessageKind{model.MessageKindControl, model.MessageKindThreadActivity} {
+		if client.messageConverterForKind(kind) != nil {
+			t.Fatalf("expected %s messages to be dropped", kind)
+		}
+	}
+	for _, kind := range []model.MessageKind{model.MessageKindText, model.MessageKindMedia, model.MessageKindCall, model.MessageKindUnknown} {
+		if client.messageConverterForKind(kind) == nil {
+			t.Fatalf("expected converter for %s messages", kind)
+		}
+	}
+}
+
+func TestConvertTeamsUnsupportedMessageIsNotice(t *testing.T) {
+	msg := model.RemoteMessage{
+		MessageType: "Some/NewThing",
+		Kind:        model.MessageKindUnknown,
+		Body:        "<weird>xml</weird>",
+	}
+	converted, err := (&TeamsClient{}).messageConverterForKind(msg.Kind)(context.Background(), nil, nil, msg)
+	if err != nil {
+		t.Fatalf("convert failed: %v", err)
+	}
+	if len(converted.Parts) != 1 {
+		t.Fatalf("expected one part, got %d", len(converted.Parts))
+	}
+	content := converted.Parts[0].Content
+	if content.MsgType != event.MsgNotice || content.Body != "Unsupported Teams message (Some/NewThing)" {
+		t.Fatalf("unexpected content: %#v", content)
+	}
+}
+
+func TestConvertTeamsMediaMessageDropsURIObjectBody(t *testing.T) {
+	msg := model.RemoteMessage{
+		MessageType:     "RichText/Media_GenericFile",
+		Kind:            model.MessageKindMedia,
+		Body:            "URIObject spec.pdf",
+		PropertiesFiles: `[{"fileName":"spec.pdf","fileInfo":{"shareUrl":"https://example.com/spec.pdf"}}]`,
+	}
+	converted, err := (&TeamsClient{}).convertTeamsMediaMessage(context.Background(), nil, nil, msg)
+	if err != nil {
+		t.Fatalf("convert failed: %v", err)
+	}
+	content := converted.Parts[0].Content
+	if content.MsgType != event.MsgText || content.Body != "Attachment: spec.pdf - https://example.com/spec.pdf" {
+		t.Fatalf("unexpected content: %#v", content)
+	}
+}
+
+func TestConvertTeamsMediaMessageAudioFallsBackToNotice(t *testing.T) {
+	msg := model.RemoteMessage{
+		MessageType: model.AudioMessageType,
+		Kind:        model.MessageKindMedia,
+		Audio:       &model.AudioMessage{ObjectID: "0-weu-d1-abc", URL: "https://api.asm.skype.com/v1/objects/0-weu-d1-abc/views/audio"},
+	}
+	converted, err := (&TeamsClient{}).convertTeamsMediaMessage(context.Background(), nil, nil, msg)
+	if err != nil {
+		t.Fatalf("convert failed: %v", err)
+	}
+	if len(converted.Parts) != 1 || converted.Parts[0].ID != "audio" {
+		t.Fatalf("unexpected parts: %#v", converted.Parts)
+	}
+	content := converted.Parts[0].Content
+	if content.MsgType != event.MsgNotice || content.Body != "Voice message (could not be bridged)" {
+		t.Fatalf("unexpected content: %#v", content)
+	}
+}
+
+func TestConvertTeamsCallMessageIsNotice(t *testing.T) {
+	msg := model.RemoteMessage{
+		MessageType: "Event/Call",
+		Kind:        model.MessageKindCall,
+		SenderName:  "Alice",
+		Call:        &model.CallEvent{CallID: "call-1", Type: model.CallMissed},
+	}
+	converted, err := (&TeamsClient{}).messageConverterForKind(msg.Kind)(context.Background(), nil, nil, msg)
+	if err != nil {
+		t.Fatalf("convert failed: %v", err)
+	}
+	content := converted.Parts[0].Content
+	if content.MsgType != event.MsgNotice || content.Body != "Missed call from Alice" {
+		t.Fatalf("unexpected content: %#v", content)
+	}
+	if meta, ok := converted.Parts[0].DBMetadata.(*teamsid.MessageMetadata); !ok || meta.CallerName != "Alice" || meta.CallID != "call-1" {
+		t.Fatalf("expected caller name and call ID in metadata, got %#v", converted.Parts[0].DBMetadata)
+	}
+}
+
+func TestConvertTeamsCallEditKeepsCaller(t *testing.T) {
+	notice := &database.Message{ID: "m1", Metadata: &teamsid.MessageMetadata{CallerName: "Alice", CallID: "call-1"}}
+	msg := model.RemoteMessage{
+		MessageType: "Event/Call",
+		Kind:        model.MessageKindCall,
+		SenderName:  "Bob",
+		Call:        &model.CallEvent{CallID: "call-1", Type: model.CallMissed},
+	}
+	converted, err := (&TeamsClient{}).convertTeamsCallEdit(context.Background(), nil, nil, []*database.Message{notice}, msg)
+	if err != nil {
+		t.Fatalf("convert edit failed: %v", err)
+	}
+	if body := converted.ModifiedParts[0].Content.Body; body != "Missed call from Alice" {
+		t.Fatalf("unexpected call notice: %q", body)
+	}
+}
+
+func TestConvertTeamsMediaMessageRendersCards(t *testing.T) {
+	msg := model.RemoteMessage{
+		MessageType: "RichText/Media_Card",
+		Kind:        model.MessageKindMedia,
+		Body:        "Card - access it on",
+		Cards: []model.Card{{
+			ContentType: model.HeroCardContentType,
+			Content:     []byte(`{"title":"Weather","text":"Sunny"}`),
+		}},
+	}
+	converted, err := (&TeamsClient{}).convertTeamsMediaMessage(context.Background(), nil, nil, msg)
+	if err != nil {
+		t.Fatalf("convert failed: %v", err)
+	}
+	content := converted.Parts[0].Content
+	if content.MsgType != event.MsgText || content.Body != "Weather\nSunny" {
+		t.Fatalf("unexpected body: %q", content.Body)
+	}
+	if content.FormattedBody != "<blockquote><strong>Weather</strong><br>Sunny</blockquote>" {
+		t.Fatalf("unexpected formatted body: %q", content.FormattedBody)
+	}
+}
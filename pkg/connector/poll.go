@@ -0,0 +1,93 @@
+package connector
+
+import (
+	"strings"
+
+	"maunium.net/go/mautrix/bridgev2"
+	"maunium.net/go/mautrix/bridgev2/database"
+	"maunium.net/go/mautrix/bridgev2/networkid"
+	"maunium.net/go/mautrix/event"
+
+	"go.mau.fi/mautrix-teams/internal/teams/model"
+	"go.mau.fi/mautrix-teams/pkg/teamsid"
+)
+
+const (
+	pollPartID    networkid.PartID = "poll"
+	pollEndPartID networkid.PartID = "poll_end"
+)
+
+// convertTeamsPoll bridges an open Forms poll as an MSC3381 poll. The rendered
+// question and options are included as the text fallback.
+func convertTeamsPoll(msg model.RemoteMessage) *bridgev2.ConvertedMessage {
+	poll := msg.Poll
+	text, _ := model.RenderPoll(poll)
+	answers := make([]map[string]any, 0, len(poll.Options))
+	for _, option := range poll.Options {
+		answers = append(answers, map[string]any{
+			"id":                      option.ID,
+			"org.matrix.msc1767.text": option.Text,
+		})
+	}
+	extra := perMessageExtra(msg)
+	if extra == nil {
+		extra = make(map[string]any, 2)
+	}
+	extra["org.matrix.msc1767.text"] = text
+	extra["org.matrix.msc3381.poll.start"] = map[string]any{
+		"kind":           "org.matrix.msc3381.poll.disclosed",
+		"max_selections": poll.MaxSelections,
+		"question":       map[string]any{"org.matrix.msc1767.text": poll.Question},
+		"answers":        answers,
+	}
+	return &bridgev2.ConvertedMessage{
+		Parts: []*bridgev2.ConvertedMessagePart{{
+			ID:      pollPartID,
+			Type:    event.EventUnstablePollStart,
+			Content: &event.MessageEventContent{Body: text},
+			Extra:   extra,
+		}},
+	}
+}
+
+// convertTeamsPollEdit handles updates of a card that was bridged as a poll.
+// Forms only exposes vote totals, not who voted, so individual responses can't
+// be bridged; once the poll closes a poll end event carrying the totals is sent.
+func convertTeamsPollEdit(msg model.RemoteMessage, start *database.Message, existingByPart map[networkid.PartID]*database.Message) (*bridgev2.ConvertedEdit, error) {
+	if msg.Poll == nil || !msg.Poll.Closed {
+		return nil, bridgev2.ErrIgnoringRemoteEvent
+	}
+	if _, ok := existingByPart[pollEndPartID]; ok {
+		return nil, bridgev2.ErrIgnoringRemoteEvent
+	}
+	text := "The poll has ended."
+	if msg.Poll.HasResults() {
+		results := make([]string, 0, len(msg.Poll.Options))
+		for _, option := range msg.Poll.Options {
+			if option.Votes >= 0 {
+				results = append(results, option.Text+": "+option.VoteLabel())
+			}
+		}
+		text += " Results: " + strings.Join(results, ", ")
+	}
+	extra := perMessageExtra(msg)
+	if extra == nil {
+		extra = make(map[string]any, 2)
+	}
+	extra["org.matrix.msc1767.text"] = text
+	extra["org.matrix.msc3381.poll.end"] = map[string]any{}
+	return &bridgev2.ConvertedEdit{
+		AddedParts: &bridgev2.ConvertedMessage{
+			Parts: []*bridgev2.ConvertedMessagePart{{
+				ID:   pollEndPartID,
+				Type: event.EventUnstablePollEnd,
+				Content: &event.MessageEventContent{
+					Body:      text,
+					RelatesTo: &event.RelatesTo{Type: event.RelReference, EventID: start.MXID},
+				},
+				Extra:      extra,
+				DBMetadata: &teamsid.MessageMetadata{EditTime: msg.EditTime.UnixMilli()},
+			}},
+		},
+	}, nil
+}
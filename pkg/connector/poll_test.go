@@ -0,0 +1,111 @@
+package connector
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"maunium.net/go/mautrix/bridgev2"
+	"maunium.net/go/mautrix/bridgev2/database"
+	"maunium.net/go/mautrix/event"
+	"maunium.net/go/mautrix/id"
+
+	"go.mau.fi/mautrix-teams/internal/teams/model"
+)
+
+func testPoll(closed bool, votes ...int) *model.Poll {
+	poll := &model.Poll{
+		ID:            "r1",
+		Question:      "Lunch?",
+		MaxSelections: 1,
+		Closed:        closed,
+		Options: []model.PollOption{
+			{ID: "c1", Text: "Pizza", Votes: -1},
+			{ID: "c2", Text: "Sushi", Votes: -1},
+		},
+	}
+	for i, count := range votes {
+		poll.Options[i].Votes = count
+	}
+	return poll
+}
+
+func TestConvertTeamsMessageOpenPoll(t *testing.T) {
+	msg := model.RemoteMessage{
+		Cards: []model.Card{{ContentType: model.AdaptiveCardContentType}},
+		Poll:  testPoll(false),
+	}
+	converted, err := (&TeamsClient{}).convertTeamsMessage(context.Background(), nil, nil, msg)
+	if err != nil {
+		t.Fatalf("convert failed: %v", err)
+	}
+	if len(converted.Parts) != 1 {
+		t.Fatalf("expected one part, got %d", len(converted.Parts))
+	}
+	part := converted.Parts[0]
+	if part.ID != pollPartID || part.Type != event.EventUnstablePollStart {
+		t.Fatalf("unexpected part: %#v", part)
+	}
+	if part.Content.Body != "Poll: Lunch?\n1. Pizza\n2. Sushi" || part.Extra["org.matrix.msc1767.text"] != part.Content.Body {
+		t.Fatalf("unexpected fallback: %q / %#v", part.Content.Body, part.Extra["org.matrix.msc1767.text"])
+	}
+	start, ok := part.Extra["org.matrix.msc3381.poll.start"].(map[string]any)
+	if !ok || start["max_selections"] != 1 {
+		t.Fatalf("unexpected poll start: %#v", part.Extra)
+	}
+	answers, ok := start["answers"].([]map[string]any)
+	if !ok || len(answers) != 2 || answers[1]["id"] != "c2" || answers[1]["org.matrix.msc1767.text"] != "Sushi" {
+		t.Fatalf("unexpected answers: %#v", start["answers"])
+	}
+}
+
+func TestConvertTeamsMessageClosedPollFallsBackToText(t *testing.T) {
+	msg := model.RemoteMessage{
+		Cards: []model.Card{{ContentType: model.AdaptiveCardContentType}},
+		Poll:  testPoll(true, 2, 1),
+	}
+	converted, err := (&TeamsClient{}).convertTeamsMessage(context.Background(), nil, nil, msg)
+	if err != nil {
+		t.Fatalf("convert failed: %v", err)
+	}
+	part := converted.Parts[0]
+	if part.Type != event.EventMessage || part.Content.Body != "Poll (closed): Lunch?\n1. Pizza (2 votes)\n2. Sushi (1 vote)" {
+		t.Fatalf("unexpected part: %q", part.Content.Body)
+	}
+}
+
+func TestConvertTeamsEditPoll(t *testing.T) {
+	start := &database.Message{ID: "1", PartID: pollPartID, MXID: id.EventID("$start")}
+	existing := []*database.Message{start}
+	editTime := time.UnixMilli(1700000000000)
+
+	open := model.RemoteMessage{EditTime: editTime, Cards: []model.Card{{}}, Poll: testPoll(false)}
+	if _, err := (&TeamsClient{}).convertTeamsEdit(context.Background(), nil, nil, existing, open); !errors.Is(err, bridgev2.ErrIgnoringRemoteEvent) {
+		t.Fatalf("expected open poll update to be ignored, got %v", err)
+	}
+
+	closed := model.RemoteMessage{EditTime: editTime, Cards: []model.Card{{}}, Poll: testPoll(true, 2, 1)}
+	edit, err := (&TeamsClient{}).convertTeamsEdit(context.Background(), nil, nil, existing, closed)
+	if err != nil {
+		t.Fatalf("convert failed: %v", err)
+	}
+	if edit.AddedParts == nil || len(edit.AddedParts.Parts) != 1 || len(edit.ModifiedParts) != 0 {
+		t.Fatalf("unexpected edit: %#v", edit)
+	}
+	end := edit.AddedParts.Parts[0]
+	if end.ID != pollEndPartID || end.Type != event.EventUnstablePollEnd {
+		t.Fatalf("unexpected end part: %#v", end)
+	}
+	if end.Content.RelatesTo == nil || end.Content.RelatesTo.Type != event.RelReference || end.Content.RelatesTo.EventID != "$start" {
+		t.Fatalf("unexpected relation: %#v", end.Content.RelatesTo)
+	}
+	if end.Content.Body != "The poll has ended. Results: Pizza: 2 votes, Sushi: 1 vote" {
+		t.Fatalf("unexpected end text: %q", end.Content.Body)
+	}
+
+	existing = append(existing, &database.Message{ID: "1", PartID: pollEndPartID})
+	if _, err = (&TeamsClient{}).convertTeamsEdit(context.Background(), nil, nil, existing, closed); !errors.Is(err, bridgev2.ErrIgnoringRemoteEvent) {
+		t.Fatalf("expected second poll end to be ignored, got %v", err)
+	}
+}
@@ -1,6 +1,21 @@
 package connector
 
-import "testing"
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"maunium.net/go/mautrix/bridgev2"
+	"maunium.net/go/mautrix/bridgev2/database"
+	"maunium.net/go/mautrix/bridgev2/networkid"
+	"maunium.net/go/mautrix/event"
+
+	"go.mau.fi/mautrix-teams/internal/teams/model"
+	"go.mau.fi/mautrix-teams/pkg/teamsid"
+)
 
 func TestNormalizeTeamsReactionMessageID(t *testing.T) {
 	tests := []struct {
@@ -31,3 +46,63 @@ func TestNormalizeTeamsReactionTargetMessageID(t *testing.T) {
 		t.Fatalf("unexpected target id: %q", got)
 	}
 }
+
+type reactionRoundTripper struct {
+	bodies *[]string
+}
+
+func (rt reactionRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, _ := io.ReadAll(req.Body)
+	*rt.bodies = append(*rt.bodies, string(body))
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader("{}")),
+		Request:    req,
+	}, nil
+}
+
+func TestMatrixReactionTeamsEchoKeepsEmojiID(t *testing.T) {
+	var bodies []string
+	client := &TeamsClient{
+		Login: &bridgev2.UserLogin{UserLogin: &database.UserLogin{ID: "login"}},
+		Meta: &teamsid.UserLoginMetadata{
+			TeamsUserID:         "8:live:me",
+			SkypeToken:          "token123",
+			SkypeTokenExpiresAt: time.Now().Add(time.Hour).Unix(),
+		},
+		consumerHTTP: &http.Client{Transport: reactionRoundTripper{bodies: &bodies}},
+	}
+	portal := &bridgev2.Portal{Portal: &database.Portal{PortalKey: networkid.PortalKey{ID: "19:thread@thread.v2"}}}
+	content := &event.ReactionEventContent{RelatesTo: event.RelatesTo{Type: event.RelAnnotation, Key: "🫠"}}
+	msg := &bridgev2.MatrixReaction{
+		MatrixEventBase: bridgev2.MatrixEventBase[*event.ReactionEventContent]{Content: content, Portal: portal},
+		TargetMessage:   &database.Message{ID: "m1"},
+	}
+	pre, err := client.PreHandleMatrixReaction(context.Background(), msg)
+	if err != nil {
+		t.Fatalf("PreHandleMatrixReaction failed: %v", err)
+	}
+	msg.PreHandleResp = &pre
+	sent, err := client.HandleMatrixReaction(context.Background(), msg)
+	if err != nil {
+		t.Fatalf("HandleMatrixReaction failed: %v", err)
+	}
+	if len(bodies) != 1 || !strings.Contains(bodies[0], `"key":"1fae0_meltingface"`) {
+		t.Fatalf("expected suffixed reaction key to be sent, got %q", bodies)
+	}
+	if sent.EmojiID != pre.EmojiID || sent.EmojiID != "1fae0" {
+		t.Fatalf("unexpected emoji ids: pre %q sent %q", pre.EmojiID, sent.EmojiID)
+	}
+
+	echo, ok := client.buildReactionSyncData([]model.MessageReaction{{
+		EmotionKey: "1fae0_meltingface",
+		Users:      []model.MessageReactionUser{{MRI: "8:live:me", TimeMS: 1}},
+	}})
+	if !ok {
+		t.Fatal("expected reaction sync data for echo")
+	}
+	reactions := echo.Users[teamsUserIDToNetworkUserID("8:live:me")].Reactions
+	if len(reactions) != 1 || reactions[0].EmojiID != sent.EmojiID || reactions[0].Emoji != "🫠" {
+		t.Fatalf("expected echo to match sent reaction %q, got %#v", sent.EmojiID, reactions)
+	}
+}
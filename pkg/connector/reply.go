@@ -0,0 +1,139 @@
+package connector
+
+import (
+	"context"
+	"html"
+	"strings"
+
+	"maunium.net/go/mautrix/bridgev2"
+	"maunium.net/go/mautrix/bridgev2/database"
+	"maunium.net/go/mautrix/bridgev2/networkid"
+
+	consumerclient "go.mau.fi/mautrix-teams/internal/teams/client"
+	"go.mau.fi/mautrix-teams/internal/teams/model"
+)
+
+const replyPreviewMaxRunes = 150
+
+// resolveReplyTarget finds the bridged message a Teams reply quotes. Without a
+// database (tests) the normalized ID is trusted as-is.
+func (c *TeamsClient) resolveReplyTarget(ctx context.Context, portal *bridgev2.Portal, reply *model.MessageReply) (networkid.MessageID, bool) {
+	if reply == nil {
+		return "", false
+	}
+	messageID := normalizeTeamsMessageID(reply.MessageID)
+	if messageID == "" {
+		return "", false
+	}
+	if c == nil || c.Main == nil || c.Main.Bridge == nil || c.Main.Bridge.DB == nil || c.Main.Bridge.DB.Message == nil || portal == nil {
+		return networkid.MessageID(messageID), true
+	}
+	for _, candidate := range []string{messageID, "msg/" + messageID} {
+		target, err := c.Main.Bridge.DB.Message.GetFirstPartByID(ctx, portal.Receiver, networkid.MessageID(candidate))
+		if err == nil && target != nil {
+			return target.ID, true
+		}
+	}
+	return "", false
+}
+
+// applyReplyTo sets the Matrix reply relation, or puts the quote back into the
+// text when the original was never bridged so the context isn't lost.
+func (c *TeamsClient) applyReplyTo(ctx context.Context, portal *bridgev2.Portal, msg model.RemoteMessage) (model.RemoteMessage, *networkid.MessageOptionalPartID) {
+	if msg.ReplyTo == nil {
+		return msg, nil
+	}
+	if targetID, ok := c.resolveReplyTarget(ctx, portal, msg.ReplyTo); ok {
+		return msg, &networkid.MessageOptionalPartID{MessageID: targetID}
+	}
+	return withReplyQuoteFallback(msg), nil
+}
+
+func withReplyQuoteFallback(msg model.RemoteMessage) model.RemoteMessage {
+	reply := msg.ReplyTo
+	if reply == nil || (reply.Preview == "" && reply.SenderName == "") {
+		return msg
+	}
+	var quoteLines []string
+	for _, line := range strings.Split(reply.Preview, "\n") {
+		quoteLines = append(quoteLines, "> "+line)
+	}
+	if reply.SenderName != "" {
+		quoteLines[0] = "> " + reply.SenderName + ": " + strings.TrimPrefix(quoteLines[0], "> ")
+	}
+	quoteHTML := "<blockquote>"
+	if reply.SenderName != "" {
+		quoteHTML += "<strong>" + html.EscapeString(reply.SenderName) + "</strong><br>"
+	}
+	quoteHTML += strings.ReplaceAll(html.EscapeString(reply.Preview), "\n", "<br>") + "</blockquote>"
+
+	formatted := strings.TrimSpace(msg.FormattedBody)
+	if formatted == "" && strings.TrimSpace(msg.Body) != "" {
+		formatted = plainTextToHTML(msg.Body)
+	}
+	msg.Body = strings.TrimSpace(strings.Join(quoteLines, "\n") + "\n\n" + msg.Body)
+	msg.FormattedBody = quoteHTML + formatted
+	return msg
+}
+
+// buildReplyQuote collects what Teams needs to render a quote card for a bridged message.
+func (c *TeamsClient) buildReplyQuote(ctx context.Context, portal *bridgev2.Portal, target *database.Message) (consumerclient.ReplyQuote, bool) {
+	if target == nil {
+		return consumerclient.ReplyQuote{}, false
+	}
+	messageID := normalizeTeamsMessageID(string(target.ID))
+	if messageID == "" {
+		return consumerclient.ReplyQuote{}, false
+	}
+	quote := consumerclient.ReplyQuote{
+		MessageID: messageID,
+		SenderID:  strings.TrimSpace(string(target.SenderID)),
+		Timestamp: target.Timestamp,
+	}
+	if quote.SenderID != "" {
+		quote.SenderName = c.cachedDisplayName(ctx, quote.SenderID)
+	}
+	quote.Preview = c.replyPreview(ctx, portal, target)
+	return quote, true
+}
+
+// replyPreview fetches the quoted Matrix event for the preview text. It is best-effort:
+// Teams still renders the quote card without one.
+func (c *TeamsClient) replyPreview(ctx context.Context, portal *bridgev2.Portal, target *database.Message) string {
+	if c == nil || c.Main == nil || c.Main.Bridge == nil || c.Main.Bridge.Bot == nil || portal == nil || portal.MXID == "" || target.MXID == "" {
+		return ""
+	}
+	evt, err := c.Main.Bridge.Bot.GetEvent(ctx, portal.MXID, target.MXID)
+	if err != nil || evt == nil {
+		return ""
+	}
+	content := evt.Content.AsMessage()
+	if content == nil {
+		return ""
+	}
+	content.RemoveReplyFallback()
+	return truncateReplyPreview(content.Body)
+}
+
+func truncateReplyPreview(body string) string {
+	body = strings.Join(strings.Fields(body), " ")
+	runes := []rune(body)
+	if len(runes) <= replyPreviewMaxRunes {
+		return body
+	}
+	return strings.TrimSpace(string(runes[:replyPreviewMaxRunes])) + "…"
+}
+
+func (c *TeamsClient) getEditReplyTarget(ctx context.Context, portal *bridgev2.Portal, editTarget *database.Message) *database.Message {
+	if editTarget == nil || editTarget.ReplyTo.MessageID == "" || portal == nil {
+		return nil
+	}
+	if c == nil || c.Main == nil || c.Main.Bridge == nil || c.Main.Bridge.DB == nil {
+		return nil
+	}
+	target, err := c.Main.Bridge.DB.Message.GetFirstOrSpecificPartByID(ctx, portal.Receiver, editTarget.ReplyTo)
+	if err != nil {
+		return nil
+	}
+	return target
+}
@@ -0,0 +1,71 @@
+package connector
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"maunium.net/go/mautrix/bridgev2/database"
+	"maunium.net/go/mautrix/bridgev2/networkid"
+
+	"go.mau.fi/mautrix-teams/internal/teams/model"
+)
+
+func TestConvertTeamsMessageSetsReplyTo(t *testing.T) {
+	msg := model.RemoteMessage{
+		Body:    "my reply",
+		ReplyTo: &model.MessageReply{MessageID: "1700000000000", SenderName: "Alice", Preview: "original"},
+	}
+	converted, err := (&TeamsClient{}).convertTeamsMessage(context.Background(), nil, nil, msg)
+	if err != nil {
+		t.Fatalf("convertTeamsMessage failed: %v", err)
+	}
+	if converted.ReplyTo == nil || converted.ReplyTo.MessageID != "1700000000000" {
+		t.Fatalf("unexpected reply target: %#v", converted.ReplyTo)
+	}
+	if converted.Parts[0].Content.Body != "my reply" {
+		t.Fatalf("expected quote to stay out of the body, got %q", converted.Parts[0].Content.Body)
+	}
+}
+
+func TestWithReplyQuoteFallback(t *testing.T) {
+	msg := withReplyQuoteFallback(model.RemoteMessage{
+		Body:    "my reply",
+		ReplyTo: &model.MessageReply{MessageID: "1", SenderName: "Alice", Preview: "line one\nline <two>"},
+	})
+	if msg.Body != "> Alice: line one\n> line <two>\n\nmy reply" {
+		t.Fatalf("unexpected body: %q", msg.Body)
+	}
+	if msg.FormattedBody != "<blockquote><strong>Alice</strong><br>line one<br>line &lt;two&gt;</blockquote>my reply" {
+		t.Fatalf("unexpected formatted body: %q", msg.FormattedBody)
+	}
+}
+
+func TestBuildReplyQuoteFromTarget(t *testing.T) {
+	target := &database.Message{
+		ID:        networkid.MessageID("msg/1700000000000"),
+		SenderID:  networkid.UserID("8:live:alice"),
+		Timestamp: time.UnixMilli(1700000000123),
+	}
+	quote, ok := (&TeamsClient{}).buildReplyQuote(context.Background(), nil, target)
+	if !ok {
+		t.Fatalf("expected quote")
+	}
+	if quote.MessageID != "1700000000000" || quote.SenderID != "8:live:alice" || quote.Timestamp.UnixMilli() != 1700000000123 {
+		t.Fatalf("unexpected quote: %#v", quote)
+	}
+	if _, ok := (&TeamsClient{}).buildReplyQuote(context.Background(), nil, nil); ok {
+		t.Fatalf("expected no quote without a target")
+	}
+}
+
+func TestTruncateReplyPreview(t *testing.T) {
+	if got := truncateReplyPreview("  hello\n  world "); got != "hello world" {
+		t.Fatalf("unexpected preview: %q", got)
+	}
+	long := strings.Repeat("a", replyPreviewMaxRunes+10)
+	if got := truncateReplyPreview(long); got != strings.Repeat("a", replyPreviewMaxRunes)+"…" {
+		t.Fatalf("unexpected truncated preview: %q", got)
+	}
+}
@@ -0,0 +1,71 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+
+	"maunium.net/go/mautrix/event"
+	"maunium.net/go/mautrix/id"
+
+	internalbridge "go.mau.fi/mautrix-teams/internal/bridge"
+)
+
+type matrixMediaDownloader func(ctx context.Context, mxcURL string, file *event.EncryptedFileInfo) ([]byte, error)
+
+type matrixAttachmentSender func(ctx context.Context, threadID, filename string, content []byte, caption string) error
+
+// sendViaAMS downloads Matrix media, uploads it to AMS and posts the uploaded
+// object with send. If AMS rejects the upload, the already downloaded bytes go
+// through the regular OneDrive attachment path instead. Media over the
+// attachment size limit is rejected before it's downloaded or uploaded.
+func sendViaAMS[T any](
+	ctx context.Context,
+	c *TeamsClient,
+	roomID id.RoomID,
+	threadID string,
+	content *event.MessageEventContent,
+	kind string,
+	download matrixMediaDownloader,
+	upload func(ctx context.Context, threadID, filename, mimeType string, data []byte) (*T, error),
+	send func(ctx context.Context, object *T) error,
+	fallback matrixAttachmentSender,
+) error {
+	info, err := internalbridge.ExtractMatrixFileInfo(content)
+	if err != nil {
+		return err
+	}
+	log := c.Login.Log.With().
+		Stringer("room_id", roomID).
+		Str("thread_id", threadID).
+		Str("mxc_url", info.MXCURL).
+		Logger()
+
+	if content.Info != nil && content.Info.Size > internalbridge.MaxAttachmentBytesV0 {
+		err = fmt.Errorf("%s exceeds max size: %d > %d", kind, content.Info.Size, internalbridge.MaxAttachmentBytesV0)
+		log.Err(err).Msg("matrix " + kind + " rejected (too large)")
+		return err
+	}
+	data, err := download(ctx, info.MXCURL, info.EncryptedFile)
+	if err != nil {
+		log.Err(err).Msg("matrix " + kind + " download failed")
+		return err
+	}
+	if len(data) > internalbridge.MaxAttachmentBytesV0 {
+		err = fmt.Errorf("%s exceeds max size: %d > %d", kind, len(data), internalbridge.MaxAttachmentBytesV0)
+		log.Err(err).Msg("matrix " + kind + " rejected (too large)")
+		return err
+	}
+	mimeType := detectMIMEType(info.FileName, info.MimeType, data)
+
+	object, err := upload(ctx, threadID, info.FileName, mimeType, data)
+	if err != nil {
+		log.Warn().Err(err).Msg("AMS " + kind + " upload failed, sending as file attachment")
+		return fallback(ctx, threadID, info.FileName, data, info.Caption)
+	}
+	if err = send(ctx, object); err != nil {
+		log.Err(err).Msg(kind + " send failed")
+		return err
+	}
+	log.Debug().Msg("Sent " + kind + " via AMS")
+	return nil
+}
@@ -0,0 +1,88 @@
+package connector
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"maunium.net/go/mautrix/bridgev2"
+	"maunium.net/go/mautrix/bridgev2/database"
+	"maunium.net/go/mautrix/event"
+	"maunium.net/go/mautrix/id"
+
+	internalbridge "go.mau.fi/mautrix-teams/internal/bridge"
+	consumerclient "go.mau.fi/mautrix-teams/internal/teams/client"
+	"go.mau.fi/mautrix-teams/pkg/teamsid"
+)
+
+func newAMSTestClient(status int, requests *int) (*TeamsClient, *consumerclient.Client) {
+	client := &TeamsClient{
+		Login: &bridgev2.UserLogin{UserLogin: &database.UserLogin{ID: "login"}},
+		Meta:  &teamsid.UserLoginMetadata{TeamsUserID: "8:live:me", SkypeToken: "token123"},
+	}
+	consumer := consumerclient.NewClient(&http.Client{Transport: statusRoundTripper{status: status, requests: requests}})
+	consumer.Token = "token123"
+	return client, consumer
+}
+
+func TestSendViaAMSRejectsOversizedMedia(t *testing.T) {
+	for _, msgType := range []event.MessageType{event.MsgImage, event.MsgAudio} {
+		requests := 0
+		client, consumer := newAMSTestClient(http.StatusCreated, &requests)
+		content := &event.MessageEventContent{
+			MsgType: msgType,
+			Body:    "big",
+			URL:     id.ContentURIString("mxc://example.com/big"),
+			Info:    &event.FileInfo{Size: internalbridge.MaxAttachmentBytesV0 + 1},
+		}
+		download := func(context.Context, string, *event.EncryptedFileInfo) ([]byte, error) {
+			t.Fatalf("%s: oversized media should not be downloaded", msgType)
+			return nil, nil
+		}
+		fallback := func(context.Context, string, string, []byte, string) error {
+			t.Fatalf("%s: oversized media should not fall back to an attachment", msgType)
+			return nil
+		}
+		var err error
+		if msgType == event.MsgImage {
+			err = client.sendInlineImage(context.Background(), consumer, "!room:example.com", "19:thread@thread.v2", content, "123", download, fallback)
+		} else {
+			content.MSC3245Voice = &event.MSC3245Voice{}
+			err = client.sendVoiceMessage(context.Background(), consumer, "!room:example.com", "19:thread@thread.v2", content, "123", download, fallback)
+		}
+		if err == nil || !strings.Contains(err.Error(), "exceeds max size") {
+			t.Fatalf("%s: expected size error, got %v", msgType, err)
+		}
+		if requests != 0 {
+			t.Fatalf("%s: expected no requests, got %d", msgType, requests)
+		}
+	}
+}
+
+func TestSendViaAMSFallsBackWhenUploadFails(t *testing.T) {
+	requests := 0
+	client, consumer := newAMSTestClient(http.StatusForbidden, &requests)
+	content := &event.MessageEventContent{
+		MsgType:  event.MsgImage,
+		Body:     "photo.png",
+		FileName: "photo.png",
+		URL:      id.ContentURIString("mxc://example.com/photo"),
+	}
+	download := func(context.Context, string, *event.EncryptedFileInfo) ([]byte, error) {
+		return []byte("\x89PNG\r\n\x1a\n"), nil
+	}
+	var fallbackName string
+	var fallbackData []byte
+	fallback := func(_ context.Context, _ string, filename string, data []byte, _ string) error {
+		fallbackName, fallbackData = filename, data
+		return nil
+	}
+	err := client.sendInlineImage(context.Background(), consumer, "!room:example.com", "19:thread@thread.v2", content, "123", download, fallback)
+	if err != nil {
+		t.Fatalf("sendInlineImage failed: %v", err)
+	}
+	if requests == 0 || fallbackName != "photo.png" || string(fallbackData) != "\x89PNG\r\n\x1a\n" {
+		t.Fatalf("expected upload attempt and attachment fallback, got %d requests, %q %q", requests, fallbackName, fallbackData)
+	}
+}
@@ -17,40 +17,40 @@ func (c *TeamsClient) SendAttachmentMessage(ctx context.Context, threadID string
 	return err
 }
 
-func (c *TeamsClient) sendAttachmentMessageWithClientMessageID(ctx context.Context, threadID string, filename string, content []byte, caption string, clientMessageID string) (string, error) {
+func (c *TeamsClient) sendAttachmentMessageWithClientMessageID(ctx context.Context, threadID string, filename string, content []byte, caption string, clientMessageID string) (*internalbridge.SentAttachment, error) {
 	if !c.IsLoggedIn() {
-		return "", bridgev2.ErrNotLoggedIn
+		return nil, bridgev2.ErrNotLoggedIn
 	}
 	if err := c.ensureValidSkypeToken(ctx); err != nil {
-		return "", err
+		return nil, err
 	}
 	threadID = strings.TrimSpace(threadID)
 	if threadID == "" {
-		return "", errors.New("missing thread id")
+		return nil, errors.New("missing thread id")
 	}
 	filename = strings.TrimSpace(filename)
 	if filename == "" {
-		return "", errors.New("missing filename")
+		return nil, errors.New("missing filename")
 	}
 	if len(content) == 0 {
-		return "", errors.New("missing content")
+		return nil, errors.New("missing content")
 	}
 	if len(content) > internalbridge.MaxAttachmentBytesV0 {
-		return "", errors.New("attachment exceeds max size")
+		return nil, errors.New("attachment exceeds max size")
 	}
 
 	consumer := c.newConsumer()
 	if consumer == nil {
-		return "", errors.New("missing consumer client")
+		return nil, errors.New("missing consumer client")
 	}
 
 	graphToken, err := c.Meta.GetGraphAccessToken()
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	httpClient := c.getConsumerHTTP()
 	if httpClient == nil {
-		return "", errors.New("missing http client")
+		return nil, errors.New("missing http client")
 	}
 	gc := graph.NewClient(httpClient)
 	gc.AccessToken = graphToken
@@ -72,10 +72,10 @@ func (c *TeamsClient) sendAttachmentMessageWithClientMessageID(ctx context.Conte
 		GenerateMessageID: gen,
 	}
 
-	sentID, err := orch.SendAttachmentMessage(ctx, threadID, filename, content, caption)
+	sent, err := orch.SendAttachment(ctx, threadID, filename, content, caption)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	c.recordSelfMessage(sentID)
-	return sentID, nil
+	c.recordSelfMessage(sent.ClientMessageID)
+	return sent, nil
 }
@@ -0,0 +1,52 @@
+package connector
+
+import (
+	"context"
+	"strings"
+
+	"maunium.net/go/mautrix/event"
+	"maunium.net/go/mautrix/id"
+
+	consumerclient "go.mau.fi/mautrix-teams/internal/teams/client"
+)
+
+// maxInlineImageWidth bounds the display size written into the Teams markup;
+// the full resolution image is still uploaded.
+const maxInlineImageWidth = 600
+
+// sendInlineImage uploads a Matrix image to AMS and posts it as an inline Teams
+// image, falling back to a OneDrive attachment if AMS rejects the upload.
+func (c *TeamsClient) sendInlineImage(
+	ctx context.Context,
+	consumer *consumerclient.Client,
+	roomID id.RoomID,
+	threadID string,
+	content *event.MessageEventContent,
+	clientMessageID string,
+	download matrixMediaDownloader,
+	fallback matrixAttachmentSender,
+) error {
+	return sendViaAMS(ctx, c, roomID, threadID, content, "image", download, consumer.UploadAMSImage,
+		func(ctx context.Context, image *consumerclient.AMSImage) error {
+			if content.Info != nil {
+				image.Width, image.Height = fitImageSize(content.Info.Width, content.Info.Height, maxInlineImageWidth)
+			}
+			var caption consumerclient.MessageBody
+			if strings.TrimSpace(content.GetCaption()) != "" {
+				caption = c.outboundMessageBody(ctx, content)
+			}
+			_, err := consumer.SendAMSImageWithID(ctx, threadID, *image, caption, c.Meta.TeamsUserID, clientMessageID)
+			return err
+		}, fallback)
+}
+
+func fitImageSize(width, height, maxWidth int) (int, int) {
+	if width <= 0 || height <= 0 {
+		return 0, 0
+	}
+	if width > maxWidth {
+		height = height * maxWidth / width
+		width = maxWidth
+	}
+	return width, max(height, 1)
+}
@@ -0,0 +1,102 @@
+package connector
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog"
+	"maunium.net/go/mautrix/bridgev2"
+	"maunium.net/go/mautrix/bridgev2/networkid"
+	"maunium.net/go/mautrix/event"
+	"maunium.net/go/mautrix/id"
+
+	internalbridge "go.mau.fi/mautrix-teams/internal/bridge"
+	consumerclient "go.mau.fi/mautrix-teams/internal/teams/client"
+	"go.mau.fi/mautrix-teams/internal/teams/model"
+)
+
+// convertTeamsAudioMessage re-uploads a Teams voice clip as an MSC3245 voice message.
+// If the clip can't be fetched, a notice is bridged instead so the message isn't lost.
+func (c *TeamsClient) convertTeamsAudioMessage(ctx context.Context, portal *bridgev2.Portal, intent bridgev2.MatrixAPI, msg model.RemoteMessage) (*bridgev2.ConvertedMessage, error) {
+	extra := perMessageExtra(msg)
+	content, err := c.reuploadAudioMessage(ctx, portal, intent, *msg.Audio)
+	if err != nil {
+		zerolog.Ctx(ctx).Warn().Err(err).Str("ams_object_id", msg.Audio.ObjectID).Msg("Failed to bridge Teams voice message")
+		content = &event.MessageEventContent{
+			MsgType: event.MsgNotice,
+			Body:    "Voice message (could not be bridged)",
+		}
+	}
+	return &bridgev2.ConvertedMessage{
+		Parts: []*bridgev2.ConvertedMessagePart{{
+			ID:      networkid.PartID("audio"),
+			Type:    event.EventMessage,
+			Content: content,
+			Extra:   extra,
+		}},
+	}, nil
+}
+
+func (c *TeamsClient) reuploadAudioMessage(ctx context.Context, portal *bridgev2.Portal, intent bridgev2.MatrixAPI, audio model.AudioMessage) (*event.MessageEventContent, error) {
+	if c == nil || intent == nil {
+		return nil, errors.New("missing matrix intent")
+	}
+	consumer := c.newConsumer()
+	if consumer == nil {
+		return nil, errors.New("missing consumer client")
+	}
+	downloaded, err := consumer.DownloadAMSObject(ctx, audio.URL, internalbridge.MaxAttachmentBytesV0)
+	if err != nil {
+		return nil, err
+	}
+	filename := audio.Filename
+	if filename == "" {
+		filename = "voice message"
+	}
+	mimeType := detectMIMEType(filename, downloaded.ContentType, downloaded.Bytes)
+	roomID := id.RoomID("")
+	if portal != nil {
+		roomID = portal.MXID
+	}
+	mxc, file, err := intent.UploadMedia(ctx, roomID, downloaded.Bytes, filename, mimeType)
+	if err != nil {
+		return nil, err
+	}
+	content := buildMediaContent(event.MsgAudio, filename, mimeType, len(downloaded.Bytes), mxc, file)
+	durationMS := int(audio.Duration.Milliseconds())
+	content.Info.Duration = durationMS
+	content.MSC1767Audio = &event.MSC1767Audio{Duration: durationMS}
+	content.MSC3245Voice = &event.MSC3245Voice{}
+	return content, nil
+}
+
+// sendVoiceMessage uploads a Matrix voice note to AMS and posts it as a Teams
+// audio message. Like images, AMS failures fall back to a OneDrive attachment.
+func (c *TeamsClient) sendVoiceMessage(
+	ctx context.Context,
+	consumer *consumerclient.Client,
+	roomID id.RoomID,
+	threadID string,
+	content *event.MessageEventContent,
+	clientMessageID string,
+	download matrixMediaDownloader,
+	fallback matrixAttachmentSender,
+) error {
+	return sendViaAMS(ctx, c, roomID, threadID, content, "voice message", download, consumer.UploadAMSAudio,
+		func(ctx context.Context, audio *consumerclient.AMSAudio) error {
+			audio.Duration = matrixAudioDuration(content)
+			_, err := consumer.SendAudioMessageWithID(ctx, threadID, *audio, c.Meta.TeamsUserID, clientMessageID)
+			return err
+		}, fallback)
+}
+
+func matrixAudioDuration(content *event.MessageEventContent) time.Duration {
+	if content.MSC1767Audio != nil && content.MSC1767Audio.Duration > 0 {
+		return time.Duration(content.MSC1767Audio.Duration) * time.Millisecond
+	}
+	if content.Info != nil && content.Info.Duration > 0 {
+		return time.Duration(content.Info.Duration) * time.Millisecond
+	}
+	return 0
+}
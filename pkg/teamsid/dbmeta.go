@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"strings"
 	"time"
+
+	"maunium.net/go/mautrix/event"
 )
 
 // Bridgev2 metadata types for mautrix-teams.
@@ -21,7 +23,26 @@ type GhostMetadata struct {
 }
 
 type MessageMetadata struct {
-	// Intentionally empty for now.
+	// EditTime is the Teams edittime (unix ms) of the last bridged revision.
+	EditTime int64 `json:"edit_time,omitempty"`
+	// EditBody is the plain text of the last edit sent from Matrix, so its echo
+	// from Teams can be ignored.
+	EditBody string `json:"edit_body,omitempty"`
+	// DriveItemID is the OneDrive item uploaded for an outbound attachment.
+	DriveItemID string `json:"drive_item_id,omitempty"`
+	// CallerName is who started the call a call notice belongs to, kept so
+	// later events of the call don't re-render it with their own sender.
+	CallerName string `json:"caller_name,omitempty"`
+	// CallID is the Teams call a call notice belongs to, so later events of the
+	// call can find the notice to edit.
+	CallID string `json:"call_id,omitempty"`
+	// Subject and Importance are the Teams subject line and importance flag of
+	// a message, kept because editing it in Teams replaces them too.
+	Subject    string `json:"subject,omitempty"`
+	Importance string `json:"importance,omitempty"`
+	// LinkPreviews are the bridged URL previews of a text part, kept so edits
+	// can reuse the uploaded thumbnails.
+	LinkPreviews []*event.BeeperLinkPreview `json:"link_previews,omitempty"`
 }
 
 type ReactionMetadata struct {